@@ -0,0 +1,155 @@
+// Package errseq provides operators for fallible streams, represented as
+// iter.Seq2[T, error].
+//
+// Operators act on the values of pairs with a nil error, and pass the pairs with
+// a non-nil error through, so that consumers can decide how to handle them.
+package errseq
+
+import "iter"
+
+func zero[T any]() (zero T) { return }
+
+// Map emits the result of calling f on every value emitted by src.
+// Error pairs are emitted with the zero value of V.
+func Map[T, V any](src iter.Seq2[T, error], f func(T) V) iter.Seq2[V, error] {
+	return func(yield func(V, error) bool) {
+		for t, err := range src {
+			if err != nil {
+				if !yield(zero[V](), err) {
+					return
+				}
+				continue
+			}
+			if !yield(f(t), nil) {
+				return
+			}
+		}
+	}
+}
+
+// TryMap emits the results of calling f on every value emitted by src, together with
+// the errors it returns. Errors don't stop the iteration.
+func TryMap[T, V any](src iter.Seq[T], f func(T) (V, error)) iter.Seq2[V, error] {
+	return func(yield func(V, error) bool) {
+		for t := range src {
+			if !yield(f(t)) {
+				return
+			}
+		}
+	}
+}
+
+// Filter emits the values emitted by src for which predicate returns true.
+// Error pairs are always emitted.
+func Filter[T any](src iter.Seq2[T, error], predicate func(T) bool) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		for t, err := range src {
+			if err == nil && !predicate(t) {
+				continue
+			}
+			if !yield(t, err) {
+				return
+			}
+		}
+	}
+}
+
+// TakeN emits the first n values emitted by src, together with the error pairs
+// emitted before the nth value. Error pairs don't count towards n.
+func TakeN[T any](src iter.Seq2[T, error], n int) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		if n <= 0 {
+			return
+		}
+		taken := 0
+		for t, err := range src {
+			if !yield(t, err) {
+				return
+			}
+			if err != nil {
+				continue
+			}
+			if taken++; taken == n {
+				return
+			}
+		}
+	}
+}
+
+// SkipN discards the first n values emitted by src and forwards the remaining ones.
+// Error pairs are never discarded and don't count towards n.
+func SkipN[T any](src iter.Seq2[T, error], n int) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		skipped := 0
+		for t, err := range src {
+			if err == nil && skipped < n {
+				skipped++
+				continue
+			}
+			if !yield(t, err) {
+				return
+			}
+		}
+	}
+}
+
+// Collect consumes src and returns the values it emitted, stopping at the first error
+// pair. In that case the values emitted before the error are returned together with it.
+func Collect[T any](src iter.Seq2[T, error]) ([]T, error) {
+	var ts []T
+	for t, err := range src {
+		if err != nil {
+			return ts, err
+		}
+		ts = append(ts, t)
+	}
+	return ts, nil
+}
+
+// Must emits the values emitted by src and panics with the error of the first error
+// pair. It is meant for tests and scripts, where errors are not expected.
+func Must[T any](src iter.Seq2[T, error]) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for t, err := range src {
+			if err != nil {
+				panic(err)
+			}
+			if !yield(t) {
+				return
+			}
+		}
+	}
+}
+
+// Ignore emits the values emitted by src and silently discards error pairs.
+func Ignore[T any](src iter.Seq2[T, error]) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for t, err := range src {
+			if err != nil {
+				continue
+			}
+			if !yield(t) {
+				return
+			}
+		}
+	}
+}
+
+// StopOnError emits the values emitted by src until the first error pair, then stores
+// its error in capture and stops. capture is left untouched if no error occurs.
+//
+// This allows to use operators for iter.Seq in the middle of a fallible pipeline,
+// checking capture once the pipeline has been consumed.
+func StopOnError[T any](src iter.Seq2[T, error], capture *error) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for t, err := range src {
+			if err != nil {
+				*capture = err
+				return
+			}
+			if !yield(t) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,201 @@
+package errseq_test
+
+import (
+	"errors"
+	"fmt"
+	"iter"
+	"slices"
+	"strconv"
+	"testing"
+
+	"github.com/empijei/itertools"
+	"github.com/empijei/itertools/errseq"
+	"github.com/google/go-cmp/cmp"
+)
+
+var errBad = errors.New("bad")
+
+// fallible emits the values of vs, emitting an errBad pair in place of negative values.
+func fallible(vs ...int) iter.Seq2[int, error] {
+	return func(yield func(int, error) bool) {
+		for _, v := range vs {
+			var err error
+			if v < 0 {
+				err = errBad
+			}
+			if !yield(v, err) {
+				return
+			}
+		}
+	}
+}
+
+// collect formats the pairs emitted by src, using "!" for error pairs.
+func collect[T any](src iter.Seq2[T, error]) []string {
+	var got []string
+	for v, err := range src {
+		if err != nil {
+			got = append(got, "!")
+			continue
+		}
+		got = append(got, fmt.Sprint(v))
+	}
+	return got
+}
+
+func TestMap(t *testing.T) {
+	tests := []struct {
+		src  []int
+		want []string
+	}{
+		{[]int{1, -1, 2}, []string{"2", "!", "4"}},
+		{nil, nil},
+	}
+	for _, tt := range tests {
+		got := collect(errseq.Map(fallible(tt.src...), func(i int) string { return strconv.Itoa(i * 2) }))
+		if diff := cmp.Diff(tt.want, got); diff != "" {
+			t.Errorf("Map(%v, double): got %v want %v diff:\n%v", tt.src, got, tt.want, diff)
+		}
+	}
+}
+
+func TestTryMap(t *testing.T) {
+	src := []string{"1", "x", "3"}
+	got := collect(errseq.TryMap(slices.Values(src), strconv.Atoi))
+	want := []string{"1", "!", "3"}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("TryMap(%v, Atoi): got %v want %v diff:\n%v", src, got, want, diff)
+	}
+}
+
+func TestFilter(t *testing.T) {
+	src := []int{1, 2, -1, 3, 4}
+	got := collect(errseq.Filter(fallible(src...), func(i int) bool { return i%2 == 0 }))
+	want := []string{"2", "!", "4"}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Filter(%v, isEven): got %v want %v diff:\n%v", src, got, want, diff)
+	}
+}
+
+func TestTakeN(t *testing.T) {
+	tests := []struct {
+		src  []int
+		n    int
+		want []string
+	}{
+		{[]int{1, -1, 2, -1, 3}, 2, []string{"1", "!", "2"}},
+		{[]int{-1, 1}, 5, []string{"!", "1"}},
+		{[]int{1, 2}, 0, nil},
+	}
+	for _, tt := range tests {
+		got := collect(errseq.TakeN(fallible(tt.src...), tt.n))
+		if diff := cmp.Diff(tt.want, got); diff != "" {
+			t.Errorf("TakeN(%v, %v): got %v want %v diff:\n%v", tt.src, tt.n, got, tt.want, diff)
+		}
+	}
+}
+
+func TestSkipN(t *testing.T) {
+	tests := []struct {
+		src  []int
+		n    int
+		want []string
+	}{
+		{[]int{1, -1, 2, 3}, 2, []string{"!", "3"}},
+		{[]int{1, 2}, 0, []string{"1", "2"}},
+		{[]int{1, 2}, 5, nil},
+	}
+	for _, tt := range tests {
+		got := collect(errseq.SkipN(fallible(tt.src...), tt.n))
+		if diff := cmp.Diff(tt.want, got); diff != "" {
+			t.Errorf("SkipN(%v, %v): got %v want %v diff:\n%v", tt.src, tt.n, got, tt.want, diff)
+		}
+	}
+}
+
+func TestCollect(t *testing.T) {
+	tests := []struct {
+		src          []int
+		want         []int
+		wantErr      error
+		wantConsumed int
+	}{
+		{[]int{1, 2, 3}, []int{1, 2, 3}, nil, 3},
+		{[]int{1, -1, 3}, []int{1}, errBad, 2},
+		{nil, nil, nil, 0},
+	}
+	for _, tt := range tests {
+		consumed := 0
+		src := func(yield func(int, error) bool) {
+			for v, err := range fallible(tt.src...) {
+				consumed++
+				if !yield(v, err) {
+					return
+				}
+			}
+		}
+		got, err := errseq.Collect(src)
+		if diff := cmp.Diff(tt.want, got); diff != "" {
+			t.Errorf("Collect(%v): got %v want %v diff:\n%v", tt.src, got, tt.want, diff)
+		}
+		if !errors.Is(err, tt.wantErr) {
+			t.Errorf("Collect(%v): got err %v want %v", tt.src, err, tt.wantErr)
+		}
+		if consumed != tt.wantConsumed {
+			t.Errorf("Collect(%v): consumed %v pairs want %v", tt.src, consumed, tt.wantConsumed)
+		}
+	}
+}
+
+func TestMust(t *testing.T) {
+	src := []int{1, 2}
+	if got := slices.Collect(errseq.Must(fallible(src...))); !slices.Equal(got, src) {
+		t.Errorf("Must(%v): got %v want %v", src, got, src)
+	}
+
+	var got []int
+	func() {
+		defer func() {
+			if r := recover(); r != errBad {
+				t.Errorf("Must(1 -1 2): got panic %v want %v", r, errBad)
+			}
+		}()
+		for v := range errseq.Must(fallible(1, -1, 2)) {
+			got = append(got, v)
+		}
+	}()
+	if want := []int{1}; !slices.Equal(got, want) {
+		t.Errorf("Must(1 -1 2): got %v before panic want %v", got, want)
+	}
+}
+
+func TestIgnore(t *testing.T) {
+	src := []int{-1, 1, -1, 2, -1}
+	got := slices.Collect(errseq.Ignore(fallible(src...)))
+	want := []int{1, 2}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Ignore(%v): got %v want %v diff:\n%v", src, got, want, diff)
+	}
+}
+
+func TestStopOnError(t *testing.T) {
+	tests := []struct {
+		src     []int
+		want    []int
+		wantErr error
+	}{
+		{[]int{1, 2, 3}, []int{2, 4, 6}, nil},
+		{[]int{1, -1, 3}, []int{2}, errBad},
+		{nil, nil, nil},
+	}
+	for _, tt := range tests {
+		var err error
+		got := slices.Collect(itertools.Map(errseq.StopOnError(fallible(tt.src...), &err), func(i int) int { return i * 2 }))
+		if diff := cmp.Diff(tt.want, got); diff != "" {
+			t.Errorf("StopOnError(%v): got %v want %v diff:\n%v", tt.src, got, tt.want, diff)
+		}
+		if err != tt.wantErr {
+			t.Errorf("StopOnError(%v): captured %v want %v", tt.src, err, tt.wantErr)
+		}
+	}
+}
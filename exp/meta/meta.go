@@ -4,6 +4,7 @@ package meta
 
 import (
 	"iter"
+	"strconv"
 
 	"github.com/empijei/itertools"
 )
@@ -36,3 +37,81 @@ func Combine[T, I, V any](
 		return b(a(s))
 	}
 }
+
+// StageCount reports how many values a stage of a [Pipeline] emitted.
+type StageCount struct {
+	Name  string
+	Count int
+}
+
+// Pipeline is a chain of named transformations from iter.Seq[T] to iter.Seq[V]
+// that keeps track of how many values every stage emitted.
+//
+// Adding a stage returns a new Pipeline and leaves the original unchanged. Every
+// Pipeline has its own counters, starting at zero: runs of a Pipeline are not counted
+// by the one it was derived from, nor by other Pipelines derived from the same one.
+// Use [NewPipeline] to create one.
+//
+// Counters are not synchronized, so a Pipeline must not be run concurrently.
+type Pipeline[T, V any] struct {
+	next   string
+	stages []*StageCount
+	// run applies the stages, counting values in the provided counters, which
+	// belong to the Pipeline being run.
+	run func(src iter.Seq[T], stages []*StageCount) iter.Seq[V]
+}
+
+// NewPipeline returns a Pipeline with no stages, which forwards its source unchanged.
+func NewPipeline[T any]() Pipeline[T, T] {
+	return Pipeline[T, T]{run: func(src iter.Seq[T], _ []*StageCount) iter.Seq[T] { return src }}
+}
+
+// Name sets the name of the next stage added to the pipeline.
+// Unnamed stages are named after their position.
+func (p Pipeline[T, V]) Name(name string) Pipeline[T, V] {
+	p.next = name
+	return p
+}
+
+// Stage appends a transformation to the pipeline.
+func (p Pipeline[T, V]) Stage(fn func(iter.Seq[V]) iter.Seq[V]) Pipeline[T, V] {
+	return Then(p, fn)
+}
+
+// Run applies all the stages of the pipeline to src.
+func (p Pipeline[T, V]) Run(src iter.Seq[T]) iter.Seq[V] {
+	return p.run(src, p.stages)
+}
+
+// Counts reports, in order, how many values every stage emitted across all runs.
+func (p Pipeline[T, V]) Counts() []StageCount {
+	counts := make([]StageCount, 0, len(p.stages))
+	for _, s := range p.stages {
+		counts = append(counts, *s)
+	}
+	return counts
+}
+
+// Then is like [Pipeline.Stage] but allows the stage to change the type of the values.
+// It is a function because methods cannot have type parameters.
+func Then[T, I, V any](p Pipeline[T, I], fn func(iter.Seq[I]) iter.Seq[V]) Pipeline[T, V] {
+	name := p.next
+	if name == "" {
+		name = "stage " + strconv.Itoa(len(p.stages))
+	}
+	stages := make([]*StageCount, 0, len(p.stages)+1)
+	for _, s := range p.stages {
+		stages = append(stages, &StageCount{Name: s.Name})
+	}
+	stages = append(stages, &StageCount{Name: name})
+	idx := len(p.stages)
+	prev := p.run
+	return Pipeline[T, V]{
+		stages: stages,
+		run: func(src iter.Seq[T], stages []*StageCount) iter.Seq[V] {
+			return itertools.Tap(fn(prev(src, stages)), func(V) {
+				stages[idx].Count++
+			})
+		},
+	}
+}
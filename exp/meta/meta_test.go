@@ -2,6 +2,7 @@ package meta_test
 
 import (
 	"slices"
+	"strconv"
 	"testing"
 
 	"github.com/empijei/itertools/exp/meta"
@@ -25,3 +26,55 @@ func TestCombineMapFilter(t *testing.T) {
 		t.Errorf("Combine(Map(*2), Filter(%%3==0))(1->6): got %v want %v diff:\n%v", got, want, diff)
 	}
 }
+
+func TestPipeline(t *testing.T) {
+	isEven := meta.Filter(func(i int) bool {
+		return i%2 == 0
+	})
+	greaterThan2 := meta.Filter(func(i int) bool {
+		return i > 2
+	})
+	p := meta.NewPipeline[int]().Name("evens").Stage(isEven).Stage(greaterThan2)
+	p2 := meta.Then(p.Name("format"), meta.Map(strconv.Itoa))
+
+	got := slices.Collect(p2.Run(slices.Values([]int{1, 2, 3, 4, 5, 6})))
+	want := []string{"4", "6"}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Pipeline(1->6): got %v want %v diff:\n%v", got, want, diff)
+	}
+
+	gotCounts := p2.Counts()
+	wantCounts := []meta.StageCount{
+		{Name: "evens", Count: 3},
+		{Name: "stage 1", Count: 2},
+		{Name: "format", Count: 2},
+	}
+	if diff := cmp.Diff(wantCounts, gotCounts); diff != "" {
+		t.Errorf("Pipeline(1->6).Counts(): got %v want %v diff:\n%v", gotCounts, wantCounts, diff)
+	}
+
+	t.Run("derived pipelines have their own counters", func(t *testing.T) {
+		base := meta.NewPipeline[int]().Name("evens").Stage(isEven)
+		p1 := base.Name("big").Stage(greaterThan2)
+		p2 := base.Name("all").Stage(meta.Filter(func(int) bool { return true }))
+		src := slices.Values([]int{1, 2, 3, 4, 5, 6})
+		for range p1.Run(src) {
+		}
+		for range p2.Run(src) {
+		}
+		tests := []struct {
+			name string
+			got  []meta.StageCount
+			want []meta.StageCount
+		}{
+			{"base", base.Counts(), []meta.StageCount{{Name: "evens", Count: 0}}},
+			{"p1", p1.Counts(), []meta.StageCount{{Name: "evens", Count: 3}, {Name: "big", Count: 2}}},
+			{"p2", p2.Counts(), []meta.StageCount{{Name: "evens", Count: 3}, {Name: "all", Count: 3}}},
+		}
+		for _, tt := range tests {
+			if diff := cmp.Diff(tt.want, tt.got); diff != "" {
+				t.Errorf("%v.Counts(): got %v want %v diff:\n%v", tt.name, tt.got, tt.want, diff)
+			}
+		}
+	})
+}
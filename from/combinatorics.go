@@ -0,0 +1,106 @@
+package from
+
+import (
+	"iter"
+	"slices"
+)
+
+// Permutations emits all the permutations of s, using Heap's algorithm.
+// The first permutation is s itself, and an empty s has a single empty permutation.
+//
+// s is not modified, but the emitted slice is reused between permutations: consumers
+// must clone it to retain it.
+func Permutations[T any](s []T) iter.Seq[[]T] {
+	return func(yield func([]T) bool) {
+		perm := slices.Clone(s)
+		if perm == nil {
+			perm = []T{}
+		}
+		if !yield(perm) {
+			return
+		}
+		// c encodes the stack state of the recursive formulation.
+		c := make([]int, len(perm))
+		for i := 1; i < len(perm); {
+			if c[i] >= i {
+				c[i] = 0
+				i++
+				continue
+			}
+			if i%2 == 0 {
+				perm[0], perm[i] = perm[i], perm[0]
+			} else {
+				perm[c[i]], perm[i] = perm[i], perm[c[i]]
+			}
+			if !yield(perm) {
+				return
+			}
+			c[i]++
+			i = 1
+		}
+	}
+}
+
+// Combinations emits all the combinations of k elements of s, in lexicographic order
+// of their positions in s. If k is 0 a single empty combination is emitted, and if k
+// is negative or greater than len(s) none is.
+//
+// The emitted slice is reused between combinations: consumers must clone it to retain it.
+func Combinations[T any](s []T, k int) iter.Seq[[]T] {
+	return func(yield func([]T) bool) {
+		if k < 0 || k > len(s) {
+			return
+		}
+		idx := make([]int, k)
+		comb := make([]T, k)
+		for i := range idx {
+			idx[i] = i
+			comb[i] = s[i]
+		}
+		for {
+			if !yield(comb) {
+				return
+			}
+			// Find the rightmost position that can still be advanced.
+			i := k - 1
+			for i >= 0 && idx[i] == len(s)-k+i {
+				i--
+			}
+			if i < 0 {
+				return
+			}
+			idx[i]++
+			comb[i] = s[idx[i]]
+			for j := i + 1; j < k; j++ {
+				idx[j] = idx[j-1] + 1
+				comb[j] = s[idx[j]]
+			}
+		}
+	}
+}
+
+// PowerSet emits all the subsets of s in bitmask order: the subset at position i
+// contains the elements of s whose index is set in the binary representation of i.
+// The first subset is empty and the last one contains all elements of s.
+//
+// The emitted slice is reused between subsets: consumers must clone it to retain it.
+// PowerSet panics if s has 64 elements or more.
+func PowerSet[T any](s []T) iter.Seq[[]T] {
+	if len(s) >= 64 {
+		panic("PowerSet: too many elements")
+	}
+	return func(yield func([]T) bool) {
+		subset := make([]T, 0, len(s))
+		for mask := uint64(0); mask < 1<<len(s); mask++ {
+			subset = subset[:0]
+			for i, t := range s {
+				if mask&(1<<i) != 0 {
+					subset = append(subset, t)
+				}
+			}
+			if !yield(subset) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package from_test
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/empijei/itertools/from"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestPermutations(t *testing.T) {
+	tests := []struct {
+		src  string
+		want []string
+	}{
+		{"", []string{""}},
+		{"a", []string{"a"}},
+		{"ab", []string{"ab", "ba"}},
+		{"abc", []string{"abc", "acb", "bac", "bca", "cab", "cba"}},
+	}
+	for _, tt := range tests {
+		src := strings.Split(tt.src, "")
+		var got []string
+		for p := range from.Permutations(src) {
+			got = append(got, strings.Join(p, ""))
+		}
+		slices.Sort(got)
+		if diff := cmp.Diff(tt.want, got); diff != "" {
+			t.Errorf("Permutations(%q): got %v want %v diff:\n%v", tt.src, got, tt.want, diff)
+		}
+		if strings.Join(src, "") != tt.src {
+			t.Errorf("Permutations(%q): modified input to %q", tt.src, src)
+		}
+	}
+
+	var n int
+	for range from.Permutations([]int{1, 2, 3, 4, 5, 6}) {
+		n++
+	}
+	if n != 720 {
+		t.Errorf("Permutations(6 values): got %v permutations want 720", n)
+	}
+}
+
+func TestCombinations(t *testing.T) {
+	tests := []struct {
+		src  string
+		k    int
+		want []string
+	}{
+		{"abcd", 2, []string{"ab", "ac", "ad", "bc", "bd", "cd"}},
+		{"abcd", 3, []string{"abc", "abd", "acd", "bcd"}},
+		{"abc", 3, []string{"abc"}},
+		{"abc", 0, []string{""}},
+		{"abc", 4, nil},
+		{"abc", -1, nil},
+	}
+	for _, tt := range tests {
+		var got []string
+		for c := range from.Combinations(strings.Split(tt.src, ""), tt.k) {
+			got = append(got, strings.Join(c, ""))
+		}
+		if diff := cmp.Diff(tt.want, got); diff != "" {
+			t.Errorf("Combinations(%q, %v): got %v want %v diff:\n%v", tt.src, tt.k, got, tt.want, diff)
+		}
+	}
+}
+
+func TestPowerSet(t *testing.T) {
+	tests := []struct {
+		src  string
+		want []string
+	}{
+		{"", []string{""}},
+		{"a", []string{"", "a"}},
+		{"abc", []string{"", "a", "b", "ab", "c", "ac", "bc", "abc"}},
+	}
+	for _, tt := range tests {
+		var got []string
+		for s := range from.PowerSet(strings.Split(tt.src, "")) {
+			got = append(got, strings.Join(s, ""))
+		}
+		if diff := cmp.Diff(tt.want, got); diff != "" {
+			t.Errorf("PowerSet(%q): got %v want %v diff:\n%v", tt.src, got, tt.want, diff)
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package from
+
+import (
+	"container/list"
+	"container/ring"
+	"iter"
+)
+
+// List emits the values of the elements of l, from front to back.
+// Nil values are emitted as the zero value of T. List panics if any other element value
+// is not of type T.
+func List[T any](l *list.List) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for e := l.Front(); e != nil; e = e.Next() {
+			if !yield(elementValue[T](e.Value)) {
+				return
+			}
+		}
+	}
+}
+
+// Ring emits the values of the elements of r, going around the ring once starting
+// from r. Nil values are emitted as the zero value of T. Ring panics if any other
+// element value is not of type T.
+func Ring[T any](r *ring.Ring) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		if r == nil {
+			return
+		}
+		e := r
+		for {
+			if !yield(elementValue[T](e.Value)) {
+				return
+			}
+			if e = e.Next(); e == r {
+				return
+			}
+		}
+	}
+}
+
+// elementValue converts the value of a container element to T, treating nil as
+// the zero value so that nil interface values can be emitted.
+func elementValue[T any](v any) T {
+	if v == nil {
+		return zero[T]()
+	}
+	return v.(T)
+}
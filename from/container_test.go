@@ -0,0 +1,65 @@
+package from_test
+
+import (
+	"container/list"
+	"container/ring"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/empijei/itertools/from"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestList(t *testing.T) {
+	l := list.New()
+	if got := slices.Collect(from.List[int](l)); len(got) != 0 {
+		t.Errorf("List(empty): got %v want none", got)
+	}
+	for _, v := range []int{1, 2, 3} {
+		l.PushBack(v)
+	}
+	got := slices.Collect(from.List[int](l))
+	want := []int{1, 2, 3}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("List(1 2 3): got %v want %v diff:\n%v", got, want, diff)
+	}
+
+	errBad := errors.New("bad")
+	errs := list.New()
+	errs.PushBack(nil)
+	errs.PushBack(errBad)
+	if got := slices.Collect(from.List[error](errs)); len(got) != 2 || got[0] != nil || got[1] != errBad {
+		t.Errorf("List(nil bad): got %v want [<nil> %v]", got, errBad)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("List[string](1 2 3): got no panic, want panic")
+		}
+	}()
+	for range from.List[string](l) {
+	}
+}
+
+func TestRing(t *testing.T) {
+	if got := slices.Collect(from.Ring[int](nil)); len(got) != 0 {
+		t.Errorf("Ring(nil): got %v want none", got)
+	}
+	r := ring.New(4)
+	for i := range 4 {
+		r.Value = i
+		r = r.Next()
+	}
+	r = r.Move(2)
+	got := slices.Collect(from.Ring[int](r))
+	want := []int{2, 3, 0, 1}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Ring(0 1 2 3 from 2): got %v want %v diff:\n%v", got, want, diff)
+	}
+
+	// Elements of a new ring hold nil values.
+	if got := slices.Collect(from.Ring[error](ring.New(2))); len(got) != 2 || got[0] != nil || got[1] != nil {
+		t.Errorf("Ring(nil nil): got %v want [<nil> <nil>]", got)
+	}
+}
@@ -0,0 +1,159 @@
+package from
+
+import (
+	"encoding"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CSVStructs reads a header row from r and then emits one value per record, with
+// the columns decoded into the fields of T.
+//
+// T must be a struct type. Columns are matched to exported fields by the field's
+// `csv:"name"` tag or, if the tag is missing, by the field name.
+// Fields tagged with `csv:"-"` and columns that don't match any field are ignored.
+//
+// Strings, booleans and numbers are parsed with the strconv package and fields
+// implementing encoding.TextUnmarshaler are parsed with it.
+// time.Time fields are parsed as RFC 3339 unless the tag specifies a layout for
+// time.Parse as its last option, e.g. `csv:"created,layout=2006-01-02"`.
+//
+// Conversion errors are emitted together with the partially decoded record, and
+// the consumer may decide whether to stop iteration or continue consuming further values.
+// Errors that prevent further reading from r are emitted last.
+func CSVStructs[T any](r *csv.Reader) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		typ := reflect.TypeFor[T]()
+		if typ.Kind() != reflect.Struct {
+			yield(zero[T](), fmt.Errorf("%v is not a struct type", typ))
+			return
+		}
+		header, err := r.Read()
+		if err != nil {
+			if err != io.EOF {
+				yield(zero[T](), err)
+			}
+			return
+		}
+		byName := map[string]int{}
+		layouts := make([]string, typ.NumField())
+		for i := range typ.NumField() {
+			f := typ.Field(i)
+			if !f.IsExported() {
+				continue
+			}
+			name, layout := csvTag(f)
+			if name == "-" {
+				continue
+			}
+			byName[name] = i
+			layouts[i] = layout
+		}
+		// columns maps each column to a field index, or -1 if it must be ignored.
+		columns := make([]int, len(header))
+		for i, name := range header {
+			f, ok := byName[name]
+			if !ok {
+				f = -1
+			}
+			columns[i] = f
+		}
+		for {
+			record, err := r.Read()
+			if err == io.EOF {
+				return
+			}
+			if err != nil {
+				var perr *csv.ParseError
+				if !yield(zero[T](), err) || !errors.As(err, &perr) {
+					return
+				}
+				continue
+			}
+			var t T
+			v := reflect.ValueOf(&t).Elem()
+			var errs []error
+			for i, s := range record {
+				if i >= len(columns) || columns[i] < 0 {
+					continue
+				}
+				if err := parseCSVField(v.Field(columns[i]), s, layouts[columns[i]]); err != nil {
+					errs = append(errs, fmt.Errorf("column %q: %w", header[i], err))
+				}
+			}
+			if !yield(t, errors.Join(errs...)) {
+				return
+			}
+		}
+	}
+}
+
+// csvTag returns the column name and the time layout for f, as specified by its
+// csv tag. The layout is the last option, so that it may contain commas.
+func csvTag(f reflect.StructField) (name, layout string) {
+	tag, ok := f.Tag.Lookup("csv")
+	if !ok {
+		return f.Name, ""
+	}
+	tag, layout, _ = strings.Cut(tag, ",layout=")
+	name, _, _ = strings.Cut(tag, ",")
+	if name == "" {
+		name = f.Name
+	}
+	return name, layout
+}
+
+func parseCSVField(v reflect.Value, s, layout string) error {
+	if layout != "" {
+		if v.Type() != reflect.TypeFor[time.Time]() {
+			return fmt.Errorf("layout specified for non-time type %v", v.Type())
+		}
+		t, err := time.Parse(layout, s)
+		if err != nil {
+			return err
+		}
+		v.Set(reflect.ValueOf(t))
+		return nil
+	}
+	if u, ok := v.Addr().Interface().(encoding.TextUnmarshaler); ok {
+		return u.UnmarshalText([]byte(s))
+	}
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(s)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(s, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		u, err := strconv.ParseUint(s, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetUint(u)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(s, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetFloat(f)
+	default:
+		return fmt.Errorf("unsupported type %v", v.Type())
+	}
+	return nil
+}
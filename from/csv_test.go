@@ -0,0 +1,89 @@
+package from_test
+
+import (
+	"encoding/csv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/empijei/itertools/from"
+	"github.com/google/go-cmp/cmp"
+)
+
+type csvRecord struct {
+	Name    string  `csv:"name"`
+	Age     int     `csv:"age"`
+	Score   float64 `csv:"score"`
+	Active  bool    `csv:"active"`
+	Notes   string
+	Ignored string `csv:"-"`
+}
+
+func TestCSVStructs(t *testing.T) {
+	src := `age,name,extra,active,score,Notes,Ignored
+30,Alice,x,true,9.5,"a, b",nope
+25,Bob,y,false,7,,
+`
+	var got []csvRecord
+	for rec, err := range from.CSVStructs[csvRecord](csv.NewReader(strings.NewReader(src))) {
+		if err != nil {
+			t.Fatalf("CSVStructs: got err %v want nil", err)
+		}
+		got = append(got, rec)
+	}
+	want := []csvRecord{
+		{Name: "Alice", Age: 30, Score: 9.5, Active: true, Notes: "a, b"},
+		{Name: "Bob", Age: 25, Score: 7},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("CSVStructs(%q): got %v want %v diff:\n%v", src, got, want, diff)
+	}
+}
+
+func TestCSVStructsConversionErrors(t *testing.T) {
+	src := `name,age
+Alice,thirty
+Bob,25
+`
+	var names []string
+	var errs int
+	for rec, err := range from.CSVStructs[csvRecord](csv.NewReader(strings.NewReader(src))) {
+		if err != nil {
+			errs++
+		}
+		names = append(names, rec.Name)
+	}
+	if errs != 1 {
+		t.Errorf("CSVStructs(%q): got %v errors want 1", src, errs)
+	}
+	if want := []string{"Alice", "Bob"}; !cmp.Equal(want, names) {
+		t.Errorf("CSVStructs(%q): got names %v want %v", src, names, want)
+	}
+}
+
+func TestCSVStructsLayout(t *testing.T) {
+	type event struct {
+		Day     time.Time `csv:"day,layout=Jan 2, 2006"`
+		Created time.Time `csv:"created"`
+		Bad     int       `csv:"bad,layout=2006"`
+	}
+	src := `day,created,bad
+"Nov 8, 2024",2024-11-08T19:04:13Z,
+`
+	var got []event
+	var errs []error
+	for rec, err := range from.CSVStructs[event](csv.NewReader(strings.NewReader(src))) {
+		got = append(got, rec)
+		errs = append(errs, err)
+	}
+	want := []event{{
+		Day:     time.Date(2024, 11, 8, 0, 0, 0, 0, time.UTC),
+		Created: time.Date(2024, 11, 8, 19, 4, 13, 0, time.UTC),
+	}}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("CSVStructs(%q): got %v want %v diff:\n%v", src, got, want, diff)
+	}
+	if len(errs) != 1 || errs[0] == nil || !strings.Contains(errs[0].Error(), `"bad"`) {
+		t.Errorf("CSVStructs(%q): got errs %v want one error for column bad", src, errs)
+	}
+}
@@ -2,12 +2,29 @@ package from
 
 import (
 	"bufio"
+	"cmp"
 	"context"
 	"errors"
+	"fmt"
+	"io"
 	"io/fs"
 	"iter"
+	"math/rand/v2"
+	"net"
+	"net/netip"
+	"os"
+	"os/exec"
+	"path"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
 )
 
+type empty = struct{}
+
+func zero[T any]() (zero T) { return }
+
 // ScannerText emits all text emitted by s.
 //
 // Cancellation must be handled by closing the source reader.
@@ -23,6 +40,393 @@ func ScannerText(s *bufio.Scanner) iter.Seq[string] {
 	}
 }
 
+// KVLines parses lines in the form key<sep>value read from r and emits the key-value pairs.
+// Keys and values are trimmed of surrounding white space.
+// Blank lines, lines starting with '#' and lines not containing sep are skipped.
+//
+// This covers .env files, most of /proc files and other simple configuration formats.
+//
+// Cancellation must be handled by closing the source reader, and read errors stop the iteration.
+func KVLines(r io.Reader, sep string) iter.Seq2[string, string] {
+	return func(yield func(string, string) bool) {
+		for line := range ScannerText(bufio.NewScanner(r)) {
+			line = strings.TrimSpace(line)
+			if line == "" || strings.HasPrefix(line, "#") {
+				continue
+			}
+			k, v, ok := strings.Cut(line, sep)
+			if !ok {
+				continue
+			}
+			if !yield(strings.TrimSpace(k), strings.TrimSpace(v)) {
+				return
+			}
+		}
+	}
+}
+
+// Lines emits the lines read from r, without the trailing "\n" or "\r\n".
+// Unlike [ScannerText] it has no limit on the line length.
+//
+// Read errors are emitted together with the partial line read before them, and stop
+// the iteration. Cancellation must be handled by closing the source reader.
+func Lines(r io.Reader) iter.Seq2[string, error] {
+	return func(yield func(string, error) bool) {
+		br := bufio.NewReader(r)
+		for {
+			line, err := br.ReadString('\n')
+			if err == io.EOF {
+				if line != "" {
+					yield(line, nil)
+				}
+				return
+			}
+			line = strings.TrimSuffix(line, "\n")
+			line = strings.TrimSuffix(line, "\r")
+			if !yield(line, err) || err != nil {
+				return
+			}
+		}
+	}
+}
+
+// Runes emits the UTF-8 encoded runes read from r. Invalid encodings are emitted as
+// utf8.RuneError.
+//
+// Cancellation must be handled by closing the source reader, and read errors stop the iteration.
+func Runes(r io.Reader) iter.Seq[rune] {
+	return func(yield func(rune) bool) {
+		rr, ok := r.(io.RuneReader)
+		if !ok {
+			rr = bufio.NewReader(r)
+		}
+		for {
+			c, _, err := rr.ReadRune()
+			if err != nil || !yield(c) {
+				return
+			}
+		}
+	}
+}
+
+// StringRunes emits the runes of s, like ranging over s would.
+func StringRunes(s string) iter.Seq[rune] {
+	return func(yield func(rune) bool) {
+		for _, c := range s {
+			if !yield(c) {
+				return
+			}
+		}
+	}
+}
+
+// Environ emits the environment variables of the process, as returned by os.Environ,
+// split in key and value.
+func Environ() iter.Seq2[string, string] {
+	return func(yield func(string, string) bool) {
+		for _, kv := range os.Environ() {
+			if kv == "" {
+				continue
+			}
+			// On Windows some variable names start with '=', like "=C:=C:\dir".
+			i := strings.IndexByte(kv[1:], '=')
+			if i < 0 {
+				continue
+			}
+			i++
+			if !yield(kv[:i], kv[i+1:]) {
+				return
+			}
+		}
+	}
+}
+
+// NetLines emits the lines read from conn, as [Lines] would, and closes conn once
+// the iteration ends.
+//
+// Reads are interrupted as soon as ctx is done, in which case the context error is
+// emitted as the final pair.
+func NetLines(ctx context.Context, conn net.Conn) iter.Seq2[string, error] {
+	return func(yield func(string, error) bool) {
+		defer conn.Close()
+		if d, ok := ctx.Deadline(); ok {
+			conn.SetReadDeadline(d)
+		}
+		stop := context.AfterFunc(ctx, func() {
+			// A deadline in the past unblocks pending reads.
+			conn.SetReadDeadline(time.Unix(1, 0))
+		})
+		defer stop()
+		for line, err := range Lines(conn) {
+			if err != nil && ctx.Err() != nil {
+				err = ctx.Err()
+			}
+			if !yield(line, err) {
+				return
+			}
+		}
+	}
+}
+
+// CommandLines starts cmd and emits the lines it writes to its standard output, as
+// [Lines] would. cmd must not have been started and its Stdout must not be set.
+//
+// The process is killed if the consumer stops the iteration early, ctx is done or
+// reading its output fails.
+// Once the output ends the process is waited for and, if it failed or ctx is done,
+// the error is emitted as the final pair.
+func CommandLines(ctx context.Context, cmd *exec.Cmd) iter.Seq2[string, error] {
+	return func(yield func(string, error) bool) {
+		stdout, err := cmd.StdoutPipe()
+		if err != nil {
+			yield("", err)
+			return
+		}
+		if err := cmd.Start(); err != nil {
+			yield("", err)
+			return
+		}
+		stop := context.AfterFunc(ctx, func() {
+			cmd.Process.Kill()
+			// Unblock reads even if children of the process hold the output open.
+			stdout.Close()
+		})
+		defer stop()
+		var readErr error
+		for line, err := range Lines(stdout) {
+			if err != nil {
+				// The process might block writing to the unread pipe.
+				cmd.Process.Kill()
+				readErr = err
+				break
+			}
+			if !yield(line, nil) {
+				cmd.Process.Kill()
+				cmd.Wait()
+				return
+			}
+		}
+		err = cmd.Wait()
+		switch {
+		case ctx.Err() != nil:
+			yield("", ctx.Err())
+		case readErr != nil:
+			// The Wait error would only report that the process was killed.
+			yield("", readErr)
+		case err != nil:
+			yield("", err)
+		}
+	}
+}
+
+// Expand emits every string emitted by src with ${var} or $var replaced according
+// to mapping, as os.Expand would.
+func Expand(src iter.Seq[string], mapping func(string) string) iter.Seq[string] {
+	return func(yield func(string) bool) {
+		for s := range src {
+			if !yield(os.Expand(s, mapping)) {
+				return
+			}
+		}
+	}
+}
+
+// MergeSortedSeq2 performs a k-way merge of the sources, which must be sorted by key
+// in ascending order, and emits all their couples sorted by key.
+// Couples with the same key are emitted in the order of the sources they come from.
+func MergeSortedSeq2[K cmp.Ordered, V any](srcs ...iter.Seq2[K, V]) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		type head struct {
+			next func() (K, V, bool)
+			k    K
+			v    V
+		}
+		heads := make([]head, 0, len(srcs))
+		for _, src := range srcs {
+			next, stop := iter.Pull2(src)
+			defer stop()
+			if k, v, ok := next(); ok {
+				heads = append(heads, head{next, k, v})
+			}
+		}
+		for len(heads) > 0 {
+			m := 0
+			for i := 1; i < len(heads); i++ {
+				if heads[i].k < heads[m].k {
+					m = i
+				}
+			}
+			h := &heads[m]
+			if !yield(h.k, h.v) {
+				return
+			}
+			var ok bool
+			if h.k, h.v, ok = h.next(); !ok {
+				heads = slices.Delete(heads, m, m+1)
+			}
+		}
+	}
+}
+
+// Generate2 emits the couples passed by gen to its yield function, and calls cleanup
+// exactly once when the iteration ends, whether gen returns, the consumer stops the
+// iteration early or a panic occurs.
+//
+// This is useful to build sources that own resources, like open files or database rows,
+// that need to be released after use. Such sources can only be consumed once:
+// iterating the returned Seq2 again emits nothing. If the returned Seq2 is never
+// consumed, cleanup is never called.
+//
+// Once the consumer stops the iteration, further calls to yield made by gen return
+// false without emitting values.
+func Generate2[T any](gen func(yield func(T, error) bool), cleanup func()) iter.Seq2[T, error] {
+	var used bool
+	return func(yield func(T, error) bool) {
+		if used {
+			return
+		}
+		used = true
+		defer cleanup()
+		stopped := false
+		gen(func(t T, err error) bool {
+			if stopped {
+				return false
+			}
+			stopped = !yield(t, err)
+			return !stopped
+		})
+	}
+}
+
+// Func emits the values returned by next until it reports that there are no more.
+// It adapts stateful "give me the next value" functions to iterators.
+func Func[T any](next func() (T, bool)) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for {
+			t, ok := next()
+			if !ok || !yield(t) {
+				return
+			}
+		}
+	}
+}
+
+// Pages lazily walks a paginated collection, such as the results of an API call,
+// and emits all of its items.
+//
+// fetch is called with the zero value of P to get the first page, and then with the
+// cursor it returned for the previous page, and reports with done whether the page
+// it returned is the last one. The next page is only fetched once the consumer has
+// consumed all the items of the current one.
+//
+// Fetch errors and the context error, if ctx is done before a fetch, are emitted and
+// stop the iteration.
+func Pages[P, T any](ctx context.Context, fetch func(ctx context.Context, cursor P) (items []T, next P, done bool, err error)) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		var cursor P
+		for {
+			if err := ctx.Err(); err != nil {
+				yield(zero[T](), err)
+				return
+			}
+			items, next, done, err := fetch(ctx, cursor)
+			if err != nil {
+				yield(zero[T](), err)
+				return
+			}
+			for _, t := range items {
+				if !yield(t, nil) {
+					return
+				}
+			}
+			if done {
+				return
+			}
+			cursor = next
+		}
+	}
+}
+
+// TimeRange emits the times from start, included, to end, excluded, every step.
+// If step is not positive no value is emitted.
+func TimeRange(start, end time.Time, step time.Duration) iter.Seq[time.Time] {
+	return func(yield func(time.Time) bool) {
+		if step <= 0 {
+			return
+		}
+		for t := start; t.Before(end); t = t.Add(step) {
+			if !yield(t) {
+				return
+			}
+		}
+	}
+}
+
+// Backoff emits an endless exponential backoff schedule: it starts from base and
+// multiplies the delay by factor at every step, capping it to max.
+// Use itertools.TakeN to limit the number of attempts.
+//
+// If jitter is not nil every delay is replaced by a random duration between zero and
+// the delay generated by jitter, to spread retries of concurrent clients ("full jitter").
+//
+// Backoff panics if base is not positive, max is less than base or factor is less than 1.
+func Backoff(base, max time.Duration, factor float64, jitter *rand.Rand) iter.Seq[time.Duration] {
+	if base <= 0 || max < base || !(factor >= 1) {
+		panic(fmt.Sprintf("Backoff: invalid schedule base %v, max %v, factor %v", base, max, factor))
+	}
+	return func(yield func(time.Duration) bool) {
+		delay := base
+		for {
+			d := delay
+			if jitter != nil {
+				// delay is at most math.MaxInt64, so the bound cannot overflow.
+				d = time.Duration(jitter.Uint64N(uint64(delay) + 1))
+			}
+			if !yield(d) {
+				return
+			}
+			// Compare before converting, as float64(max) may not be representable
+			// as a Duration.
+			if next := float64(delay) * factor; next >= float64(max) {
+				delay = max
+			} else {
+				delay = time.Duration(next)
+			}
+		}
+	}
+}
+
+// IPRange emits all the addresses in p, in ascending order, including the network
+// and broadcast addresses. Invalid prefixes emit nothing.
+func IPRange(p netip.Prefix) iter.Seq[netip.Addr] {
+	return func(yield func(netip.Addr) bool) {
+		if !p.IsValid() {
+			return
+		}
+		for a := p.Masked().Addr(); a.IsValid() && p.Contains(a); a = a.Next() {
+			if !yield(a) {
+				return
+			}
+		}
+	}
+}
+
+// AddrRange emits all the addresses from first to last, both included, in ascending
+// order. Nothing is emitted if the addresses are invalid, belong to different families
+// or first is greater than last.
+func AddrRange(first, last netip.Addr) iter.Seq[netip.Addr] {
+	return func(yield func(netip.Addr) bool) {
+		if !first.IsValid() || first.BitLen() != last.BitLen() {
+			return
+		}
+		for a := first; a.IsValid() && a.Compare(last) <= 0; a = a.Next() {
+			if !yield(a) {
+				return
+			}
+		}
+	}
+}
+
 // Chan emits all values received on src and stops whenever src is closed or the context is cancelled.
 func Chan[T any](ctx context.Context, src <-chan T) iter.Seq[T] {
 	return func(yield func(T) bool) {
@@ -42,6 +446,66 @@ func Chan[T any](ctx context.Context, src <-chan T) iter.Seq[T] {
 	}
 }
 
+// ChanDrain is like [Chan], but if the iteration ends before src is closed, because the
+// consumer stopped early or the context was cancelled, it spawns a goroutine that
+// receives and discards values from src until it is closed.
+// This guarantees that producers sending on src are never blocked forever.
+func ChanDrain[T any](ctx context.Context, src <-chan T) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		closed := false
+		defer func() {
+			if !closed {
+				go func() {
+					for range src {
+					}
+				}()
+			}
+		}()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case t, ok := <-src:
+				if !ok {
+					closed = true
+					return
+				}
+				if !yield(t) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// MergeChans emits all values received on any of chans, as they arrive, and stops
+// once all of them are closed or the context is cancelled.
+func MergeChans[T any](ctx context.Context, chans ...<-chan T) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		// The first case is always the context, the others are the open channels.
+		cases := make([]reflect.SelectCase, 0, len(chans)+1)
+		cases = append(cases, reflect.SelectCase{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(ctx.Done())})
+		for _, c := range chans {
+			cases = append(cases, reflect.SelectCase{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(c)})
+		}
+		for len(cases) > 1 {
+			i, v, ok := reflect.Select(cases)
+			if i == 0 {
+				return
+			}
+			if !ok {
+				cases = slices.Delete(cases, i, i+1)
+				continue
+			}
+			// Interface types hold nil values, which cannot be type asserted.
+			t, _ := v.Interface().(T)
+			if !yield(t) {
+				return
+			}
+		}
+	}
+}
+
 // DirStep represents a step in a directory Walk.
 type DirStep struct {
 	// FullPath represents the path anchored to the root walk directory.
@@ -49,25 +513,421 @@ type DirStep struct {
 	Path string
 	// Entry is the DirEntry that would be passed to fs.WalkDirFunc.
 	Entry fs.DirEntry
+
+	skip *bool
+}
+
+// SkipDir makes DirWalk not descend into the directory of this step, as returning
+// fs.SkipDir from fs.WalkDirFunc would.
+// It must be called before asking for the next step, and has no effect for steps that
+// are not directories.
+func (ds DirStep) SkipDir() {
+	if ds.skip != nil && ds.Entry != nil && ds.Entry.IsDir() {
+		*ds.skip = true
+	}
+}
+
+// DirWalkOption configures [DirWalk].
+type DirWalkOption func(*dirWalkOptions)
+
+type dirWalkOptions struct {
+	maxDepth int
+	include  []string
+	exclude  []string
+}
+
+// WithMaxDepth makes [DirWalk] not descend more than depth levels below root.
+// The root is at depth 0.
+func WithMaxDepth(depth int) DirWalkOption {
+	return func(o *dirWalkOptions) {
+		o.maxDepth = depth
+	}
+}
+
+// WithInclude makes [DirWalk] only emit files whose base name matches at least one of
+// the patterns, with the syntax of path.Match. Directories are not affected.
+func WithInclude(patterns ...string) DirWalkOption {
+	return func(o *dirWalkOptions) {
+		o.include = append(o.include, patterns...)
+	}
+}
+
+// WithExclude makes [DirWalk] skip entries whose base name matches at least one of
+// the patterns, with the syntax of path.Match. Excluded directories are not descended into.
+func WithExclude(patterns ...string) DirWalkOption {
+	return func(o *dirWalkOptions) {
+		o.exclude = append(o.exclude, patterns...)
+	}
+}
+
+// matchAny reports whether name matches any of the patterns, which must be valid.
+func matchAny(patterns []string, name string) bool {
+	for _, p := range patterns {
+		if ok, _ := path.Match(p, name); ok {
+			return true
+		}
+	}
+	return false
 }
 
 // DirWalk emits all entries for root and its subdirectories.
 // Errors are forwarded, and the consumer may decide wether to stop iteration or continue consuming further values.
+// Consumers can prune subtrees by calling [DirStep.SkipDir].
 //
 // Use os.DirFS(path) to create fsys from disk.
-func DirWalk(ctx context.Context, fsys fs.FS, root string) iter.Seq2[DirStep, error] {
+func DirWalk(ctx context.Context, fsys fs.FS, root string, opts ...DirWalkOption) iter.Seq2[DirStep, error] {
+	o := dirWalkOptions{maxDepth: -1}
+	for _, opt := range opts {
+		opt(&o)
+	}
 	return func(yield func(DirStep, error) bool) {
-		fs.WalkDir(fsys, root, func(path string, d fs.DirEntry, err error) error {
+		for _, p := range slices.Concat(o.include, o.exclude) {
+			if _, err := path.Match(p, ""); err != nil {
+				yield(DirStep{}, err)
+				return
+			}
+		}
+		root := path.Clean(root)
+		rootDepth := strings.Count(root, "/")
+		if root == "." {
+			rootDepth = -1
+		}
+		fs.WalkDir(fsys, root, func(p string, d fs.DirEntry, err error) error {
 			select {
 			case <-ctx.Done():
 				yield(DirStep{}, ctx.Err())
 				return ctx.Err()
 			default:
 			}
-			if !yield(DirStep{Path: path, Entry: d}, err) {
+			isDir := d != nil && d.IsDir()
+			if p != root && err == nil {
+				if matchAny(o.exclude, d.Name()) {
+					if isDir {
+						return fs.SkipDir
+					}
+					return nil
+				}
+				if !isDir && len(o.include) > 0 && !matchAny(o.include, d.Name()) {
+					return nil
+				}
+			}
+			var skip bool
+			if !yield(DirStep{Path: p, Entry: d, skip: &skip}, err) {
 				return errors.New("consumer stopped")
 			}
+			depth := 0
+			if p != root {
+				depth = strings.Count(p, "/") - rootDepth
+			}
+			if isDir && err == nil && (skip || depth == o.maxDepth) {
+				return fs.SkipDir
+			}
 			return nil
 		})
 	}
 }
+
+// Glob emits the names of all files in fsys matching pattern, in lexical order, with
+// the syntax of path.Match. Like fs.Glob it doesn't support "**".
+//
+// Unlike fs.Glob the file system is walked lazily, skipping directories that
+// cannot contain matches. Read errors are forwarded, and the consumer may decide
+// whether to stop iteration or continue consuming further values.
+func Glob(fsys fs.FS, pattern string) iter.Seq2[string, error] {
+	return func(yield func(string, error) bool) {
+		if _, err := path.Match(pattern, ""); err != nil {
+			yield("", err)
+			return
+		}
+		parts := strings.Split(pattern, "/")
+		fs.WalkDir(fsys, ".", func(p string, d fs.DirEntry, err error) error {
+			if p == "." {
+				if err != nil && !yield("", err) {
+					return fs.SkipAll
+				}
+				return nil
+			}
+			depth := strings.Count(p, "/") + 1
+			// Match against as many pattern elements as p has, to prune early.
+			if ok, _ := path.Match(strings.Join(parts[:depth], "/"), p); !ok {
+				if d != nil && d.IsDir() {
+					return fs.SkipDir
+				}
+				return nil
+			}
+			if depth == len(parts) {
+				if !yield(p, nil) {
+					return fs.SkipAll
+				}
+				if d != nil && d.IsDir() {
+					return fs.SkipDir
+				}
+				return nil
+			}
+			if err != nil && !yield("", err) {
+				return fs.SkipAll
+			}
+			return nil
+		})
+	}
+}
+
+// FileOp is the kind of change reported by a [FileEvent].
+type FileOp int
+
+const (
+	// Created is reported for entries that didn't exist in the previous scan.
+	Created FileOp = iota + 1
+	// Modified is reported for files whose size, mode or modification time changed.
+	Modified
+	// Removed is reported for entries that existed in the previous scan.
+	Removed
+)
+
+func (op FileOp) String() string {
+	switch op {
+	case Created:
+		return "created"
+	case Modified:
+		return "modified"
+	case Removed:
+		return "removed"
+	}
+	return fmt.Sprintf("FileOp(%d)", int(op))
+}
+
+// FileEvent is a change detected by [WatchDir].
+type FileEvent struct {
+	// Path is the path of the entry, anchored to the watched root as in [DirStep].
+	Path string
+	Op   FileOp
+}
+
+// fileState is the subset of fs.FileInfo used to detect changes.
+type fileState struct {
+	size    int64
+	mode    fs.FileMode
+	modTime time.Time
+}
+
+// WatchDir polls root and its subdirectories every interval and emits the changes
+// detected since the previous scan, sorted by path. Entries that exist when WatchDir
+// starts are not reported.
+//
+// Scan errors are forwarded, and the consumer may decide whether to stop iteration
+// or continue watching. Once ctx is done the context error is emitted as the final pair.
+// A non-positive interval is reported as the only error.
+func WatchDir(ctx context.Context, fsys fs.FS, root string, interval time.Duration) iter.Seq2[FileEvent, error] {
+	return func(yield func(FileEvent, error) bool) {
+		if interval <= 0 {
+			yield(FileEvent{}, errors.New("interval must be positive"))
+			return
+		}
+		scan := func() (map[string]fileState, []error) {
+			state := map[string]fileState{}
+			var errs []error
+			fs.WalkDir(fsys, root, func(p string, d fs.DirEntry, err error) error {
+				if err == nil {
+					var info fs.FileInfo
+					if info, err = d.Info(); err == nil {
+						state[p] = fileState{info.Size(), info.Mode(), info.ModTime()}
+						return nil
+					}
+				}
+				// Entries removed while scanning are reported in the next scan.
+				if !errors.Is(err, fs.ErrNotExist) {
+					errs = append(errs, err)
+				}
+				return nil
+			})
+			return state, errs
+		}
+		prev, errs := scan()
+		t := time.NewTicker(interval)
+		defer t.Stop()
+		for {
+			for _, err := range errs {
+				if !yield(FileEvent{}, err) {
+					return
+				}
+			}
+			select {
+			case <-ctx.Done():
+				yield(FileEvent{}, ctx.Err())
+				return
+			case <-t.C:
+			}
+			var cur map[string]fileState
+			cur, errs = scan()
+			var events []FileEvent
+			for p, st := range cur {
+				old, ok := prev[p]
+				switch {
+				case !ok:
+					events = append(events, FileEvent{p, Created})
+				case !st.mode.IsDir() && (st.size != old.size || st.mode != old.mode || !st.modTime.Equal(old.modTime)):
+					events = append(events, FileEvent{p, Modified})
+				}
+			}
+			for p := range prev {
+				if _, ok := cur[p]; !ok {
+					events = append(events, FileEvent{p, Removed})
+				}
+			}
+			slices.SortFunc(events, func(a, b FileEvent) int {
+				return strings.Compare(a.Path, b.Path)
+			})
+			for _, e := range events {
+				if !yield(e, nil) {
+					return
+				}
+			}
+			prev = cur
+		}
+	}
+}
+
+// Chunk represents a section of the data read by [ReaderAt].
+type Chunk struct {
+	// Offset is the position of the first byte of Data in the source.
+	Offset int64
+	// Data is the content of the section. It is never reused between chunks.
+	Data []byte
+}
+
+// ReaderAt emits the first size bytes of r split in chunks of chunk bytes. The last chunk may be shorter.
+// Every chunk is read into a newly allocated buffer, so chunks can be safely retained or
+// processed in parallel.
+// Errors are forwarded, and the consumer may decide whether to stop iteration or continue consuming further chunks.
+func ReaderAt(r io.ReaderAt, size int64, chunk int) iter.Seq2[Chunk, error] {
+	return func(yield func(Chunk, error) bool) {
+		if chunk <= 0 {
+			yield(Chunk{}, errors.New("chunk size must be positive"))
+			return
+		}
+		for off := int64(0); off < size; off += int64(chunk) {
+			buf := make([]byte, min(int64(chunk), size-off))
+			n, err := r.ReadAt(buf, off)
+			if err == io.EOF && n == len(buf) {
+				err = nil
+			}
+			if !yield(Chunk{Offset: off, Data: buf[:n]}, err) {
+				return
+			}
+		}
+	}
+}
+
+// ReaderChunks emits the content of r in blocks of size bytes. The last block may be shorter.
+// Every block is read into a newly allocated buffer, so blocks can be safely retained.
+//
+// Read errors are emitted together with the data read before them, and stop the iteration.
+func ReaderChunks(r io.Reader, size int) iter.Seq2[[]byte, error] {
+	return func(yield func([]byte, error) bool) {
+		if size <= 0 {
+			yield(nil, errors.New("chunk size must be positive"))
+			return
+		}
+		for b, err := range ReaderChunksBuffer(r, make([]byte, size)) {
+			if !yield(slices.Clone(b), err) {
+				return
+			}
+		}
+	}
+}
+
+// ReaderChunksBuffer is like [ReaderChunks] but reads every block into buf, emitting
+// blocks of len(buf) bytes without allocating.
+// Emitted blocks are only valid until the consumer asks for the next one.
+func ReaderChunksBuffer(r io.Reader, buf []byte) iter.Seq2[[]byte, error] {
+	return func(yield func([]byte, error) bool) {
+		if len(buf) == 0 {
+			yield(nil, errors.New("chunk size must be positive"))
+			return
+		}
+		for {
+			n, err := io.ReadFull(r, buf)
+			switch err {
+			case io.EOF:
+				return
+			case nil:
+			case io.ErrUnexpectedEOF:
+				yield(buf[:n], nil)
+				return
+			default:
+				yield(buf[:n], err)
+				return
+			}
+			if !yield(buf[:n], nil) {
+				return
+			}
+		}
+	}
+}
+
+// Peeker allows to check whether an iterator has values left, and to inspect the next one,
+// without committing to consume it.
+type Peeker[T any] struct {
+	next    func() (T, bool)
+	stop    func()
+	head    T
+	hasHead bool
+	done    bool
+}
+
+// Peekable wraps src in a [Peeker].
+//
+// Once the Peeker is not needed anymore, either its Seq must be fully consumed or
+// Stop must be called to release the resources associated with src.
+func Peekable[T any](src iter.Seq[T]) *Peeker[T] {
+	next, stop := iter.Pull(src)
+	return &Peeker[T]{next: next, stop: stop}
+}
+
+func (p *Peeker[T]) fill() {
+	if p.hasHead || p.done {
+		return
+	}
+	p.head, p.hasHead = p.next()
+	if !p.hasHead {
+		p.Stop()
+	}
+}
+
+// HasNext reports whether the source has at least one more value.
+func (p *Peeker[T]) HasNext() bool {
+	p.fill()
+	return p.hasHead
+}
+
+// Peek returns the next value without consuming it.
+// If there are no values left it returns the zero value.
+func (p *Peeker[T]) Peek() T {
+	p.fill()
+	return p.head
+}
+
+// Seq emits the remaining values, including the peeked one, if any.
+func (p *Peeker[T]) Seq() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for {
+			p.fill()
+			if !p.hasHead {
+				return
+			}
+			t := p.head
+			p.head, p.hasHead = zero[T](), false
+			if !yield(t) {
+				return
+			}
+		}
+	}
+}
+
+// Stop releases the resources associated with the source.
+// After Stop is called the Peeker behaves as if the source was exhausted.
+func (p *Peeker[T]) Stop() {
+	p.done = true
+	p.head, p.hasHead = zero[T](), false
+	p.stop()
+}
@@ -3,12 +3,28 @@ package from_test
 import (
 	"bufio"
 	"context"
+	"errors"
+	"fmt"
+	"io"
 	"io/fs"
+	"iter"
+	"math"
+	"math/rand/v2"
+	"net"
+	"net/netip"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
 	"slices"
 	"strings"
 	"testing"
 	"testing/fstest"
+	"testing/iotest"
+	"time"
+	"unicode/utf8"
 
+	"github.com/empijei/itertools"
 	"github.com/empijei/itertools/from"
 	"github.com/google/go-cmp/cmp"
 )
@@ -34,6 +50,531 @@ last line`
 	}
 }
 
+func TestKVLines(t *testing.T) {
+	src := `# Database config
+DB_HOST = localhost
+DB_PORT=5432
+
+not a pair
+EMPTY=
+URL=http://example.com/?a=b
+`
+	var got [][2]string
+	for k, v := range from.KVLines(strings.NewReader(src), "=") {
+		got = append(got, [2]string{k, v})
+	}
+	want := [][2]string{
+		{"DB_HOST", "localhost"},
+		{"DB_PORT", "5432"},
+		{"EMPTY", ""},
+		{"URL", "http://example.com/?a=b"},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("KVLines(%q, =): got %v want %v diff:\n%v", src, got, want, diff)
+	}
+}
+
+func TestLines(t *testing.T) {
+	long := strings.Repeat("x", 100_000)
+	tests := []struct {
+		src  string
+		want []string
+	}{
+		{"", nil},
+		{"foo\nbar", []string{"foo", "bar"}},
+		{"foo\r\nbar\n", []string{"foo", "bar"}},
+		{"\n\nfoo", []string{"", "", "foo"}},
+		{long + "\n" + long, []string{long, long}},
+	}
+	for _, tt := range tests {
+		var got []string
+		for line, err := range from.Lines(strings.NewReader(tt.src)) {
+			if err != nil {
+				t.Fatalf("Lines(%.20q): got err %v want nil", tt.src, err)
+			}
+			got = append(got, line)
+		}
+		if diff := cmp.Diff(tt.want, got); diff != "" {
+			t.Errorf("Lines(%.20q): got %.50q want %.50q diff:\n%.200v", tt.src, got, tt.want, diff)
+		}
+	}
+
+	t.Run("read error", func(t *testing.T) {
+		readErr := errors.New("read failed")
+		r := io.MultiReader(strings.NewReader("foo\nba"), iotest.ErrReader(readErr))
+		var got []string
+		var errs []error
+		for line, err := range from.Lines(r) {
+			got = append(got, line)
+			errs = append(errs, err)
+		}
+		want := []string{"foo", "ba"}
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Errorf("Lines(foo ba error): got %q want %q diff:\n%v", got, want, diff)
+		}
+		if len(errs) != 2 || errs[0] != nil || !errors.Is(errs[1], readErr) {
+			t.Errorf("Lines(foo ba error): got errs %v want [nil %v]", errs, readErr)
+		}
+	})
+}
+
+func TestRunes(t *testing.T) {
+	tests := []struct {
+		src  string
+		want []rune
+	}{
+		{"", nil},
+		{"héllo, 世界", []rune("héllo, 世界")},
+		{"bad \xff", []rune{'b', 'a', 'd', ' ', utf8.RuneError}},
+	}
+	for _, tt := range tests {
+		if got := slices.Collect(from.StringRunes(tt.src)); !slices.Equal(got, tt.want) {
+			t.Errorf("StringRunes(%q): got %q want %q", tt.src, got, tt.want)
+		}
+		if got := slices.Collect(from.Runes(strings.NewReader(tt.src))); !slices.Equal(got, tt.want) {
+			t.Errorf("Runes(%q): got %q want %q", tt.src, got, tt.want)
+		}
+		// Hide the io.RuneReader implementation of strings.Reader.
+		r := io.MultiReader(strings.NewReader(tt.src))
+		if got := slices.Collect(from.Runes(r)); !slices.Equal(got, tt.want) {
+			t.Errorf("Runes(%q) without RuneReader: got %q want %q", tt.src, got, tt.want)
+		}
+	}
+}
+
+func TestEnviron(t *testing.T) {
+	t.Setenv("ITERTOOLS_TEST_VAR", "a=b")
+	t.Setenv("ITERTOOLS_TEST_EMPTY", "")
+	got := map[string]string{}
+	for k, v := range from.Environ() {
+		if strings.HasPrefix(k, "ITERTOOLS_TEST_") {
+			got[k] = v
+		}
+	}
+	want := map[string]string{"ITERTOOLS_TEST_VAR": "a=b", "ITERTOOLS_TEST_EMPTY": ""}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Environ(): got %v want %v diff:\n%v", got, want, diff)
+	}
+}
+
+func TestNetLines(t *testing.T) {
+	t.Run("values are emitted", func(t *testing.T) {
+		client, server := net.Pipe()
+		go func() {
+			io.WriteString(server, "foo\r\nbar\nbaz")
+			server.Close()
+		}()
+		var got []string
+		for line, err := range from.NetLines(context.Background(), client) {
+			if err != nil {
+				t.Fatalf("NetLines: got err %v want nil", err)
+			}
+			got = append(got, line)
+		}
+		want := []string{"foo", "bar", "baz"}
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Errorf("NetLines: got %v want %v diff:\n%v", got, want, diff)
+		}
+	})
+	t.Run("cancellation closes the connection", func(t *testing.T) {
+		client, server := net.Pipe()
+		defer server.Close()
+		go io.WriteString(server, "foo\n")
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		var errs []error
+		for line, err := range from.NetLines(ctx, client) {
+			if line == "foo" {
+				cancel()
+			}
+			errs = append(errs, err)
+		}
+		if len(errs) != 2 || !errors.Is(errs[1], context.Canceled) {
+			t.Errorf("NetLines cancelled: got errs %v want [nil %v]", errs, context.Canceled)
+		}
+		if _, err := server.Write([]byte("bar\n")); err == nil {
+			t.Errorf("NetLines cancelled: write to peer succeeded, want connection closed")
+		}
+	})
+}
+
+func TestCommandLines(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh not available")
+	}
+	tests := []struct {
+		script  string
+		want    []string
+		wantErr bool
+	}{
+		{"echo foo; echo bar", []string{"foo", "bar"}, false},
+		{"echo foo; exit 3", []string{"foo"}, true},
+		{"true", nil, false},
+	}
+	for _, tt := range tests {
+		var got []string
+		var gotErr error
+		for line, err := range from.CommandLines(context.Background(), exec.Command("sh", "-c", tt.script)) {
+			if err != nil {
+				gotErr = err
+				continue
+			}
+			got = append(got, line)
+		}
+		if diff := cmp.Diff(tt.want, got); diff != "" {
+			t.Errorf("CommandLines(%q): got %v want %v diff:\n%v", tt.script, got, tt.want, diff)
+		}
+		if (gotErr != nil) != tt.wantErr {
+			t.Errorf("CommandLines(%q): got err %v want err %v", tt.script, gotErr, tt.wantErr)
+		}
+	}
+
+	t.Run("early stop", func(t *testing.T) {
+		cmd := exec.Command("sh", "-c", "while true; do echo y; done")
+		var n int
+		for range from.CommandLines(context.Background(), cmd) {
+			n++
+			if n == 3 {
+				break
+			}
+		}
+		if cmd.ProcessState == nil {
+			t.Errorf("CommandLines(endless) stopped early: process was not waited for")
+		}
+	})
+
+	t.Run("cancellation", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		var errs []error
+		for line, err := range from.CommandLines(ctx, exec.Command("sh", "-c", "echo foo; sleep 10; echo bar")) {
+			if line == "foo" {
+				cancel()
+			}
+			errs = append(errs, err)
+		}
+		if len(errs) != 2 || !errors.Is(errs[1], context.Canceled) {
+			t.Errorf("CommandLines(sleep) cancelled: got errs %v want [nil %v]", errs, context.Canceled)
+		}
+	})
+}
+
+func TestExpand(t *testing.T) {
+	src := []string{"host=$HOST", "url=http://${HOST}:${PORT}/", "plain", "missing=$MISSING"}
+	vars := map[string]string{"HOST": "localhost", "PORT": "8080"}
+	got := slices.Collect(from.Expand(slices.Values(src), func(k string) string { return vars[k] }))
+	want := []string{"host=localhost", "url=http://localhost:8080/", "plain", "missing="}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Expand(%v): got %v want %v diff:\n%v", src, got, want, diff)
+	}
+}
+
+func TestMergeSortedSeq2(t *testing.T) {
+	kvs := func(pairs ...[2]string) iter.Seq2[string, string] {
+		return func(yield func(string, string) bool) {
+			for _, p := range pairs {
+				if !yield(p[0], p[1]) {
+					return
+				}
+			}
+		}
+	}
+	merged := from.MergeSortedSeq2(
+		kvs([2]string{"a", "1"}, [2]string{"c", "1"}, [2]string{"d", "1"}),
+		kvs(),
+		kvs([2]string{"b", "2"}, [2]string{"c", "2"}, [2]string{"e", "2"}),
+		kvs([2]string{"a", "3"}),
+	)
+	var got [][2]string
+	for k, v := range merged {
+		got = append(got, [2]string{k, v})
+	}
+	want := [][2]string{
+		{"a", "1"}, {"a", "3"}, {"b", "2"}, {"c", "1"}, {"c", "2"}, {"d", "1"}, {"e", "2"},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("MergeSortedSeq2: got %v want %v diff:\n%v", got, want, diff)
+	}
+}
+
+func TestGenerate2(t *testing.T) {
+	gen := func(yield func(int, error) bool) {
+		for i := range 5 {
+			if !yield(i, nil) {
+				return
+			}
+		}
+		yield(0, errors.New("done"))
+	}
+	t.Run("drained", func(t *testing.T) {
+		cleanups := 0
+		seq := from.Generate2(gen, func() { cleanups++ })
+		var got []int
+		var errs int
+		for v, err := range seq {
+			if err != nil {
+				errs++
+				continue
+			}
+			got = append(got, v)
+		}
+		if want := []int{0, 1, 2, 3, 4}; !cmp.Equal(want, got) {
+			t.Errorf("Generate2: got %v want %v", got, want)
+		}
+		if errs != 1 {
+			t.Errorf("Generate2: got %v errors want 1", errs)
+		}
+		for range seq {
+			t.Errorf("Generate2 second iteration: got values want none")
+		}
+		if cleanups != 1 {
+			t.Errorf("Generate2: got %v cleanups want 1", cleanups)
+		}
+	})
+	t.Run("stopped early", func(t *testing.T) {
+		cleanups := 0
+		for v := range from.Generate2(gen, func() { cleanups++ }) {
+			if v == 2 {
+				break
+			}
+		}
+		if cleanups != 1 {
+			t.Errorf("Generate2 stopped early: got %v cleanups want 1", cleanups)
+		}
+	})
+	t.Run("misbehaving generator", func(t *testing.T) {
+		cleanups := 0
+		ignoresStop := func(yield func(int, error) bool) {
+			for i := range 5 {
+				yield(i, nil)
+			}
+		}
+		var got []int
+		for v := range from.Generate2(ignoresStop, func() { cleanups++ }) {
+			got = append(got, v)
+			if v == 1 {
+				break
+			}
+		}
+		if want := []int{0, 1}; !cmp.Equal(want, got) {
+			t.Errorf("Generate2 ignoring stop: got %v want %v", got, want)
+		}
+		if cleanups != 1 {
+			t.Errorf("Generate2 ignoring stop: got %v cleanups want 1", cleanups)
+		}
+	})
+}
+
+func TestFunc(t *testing.T) {
+	i := 0
+	next := func() (int, bool) {
+		i++
+		return i * i, i <= 4
+	}
+	got := slices.Collect(from.Func(next))
+	want := []int{1, 4, 9, 16}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Func(squares up to 4): got %v want %v diff:\n%v", got, want, diff)
+	}
+
+	calls := 0
+	counting := func() (int, bool) {
+		calls++
+		return calls, true
+	}
+	for v := range from.Func(counting) {
+		if v == 3 {
+			break
+		}
+	}
+	if calls != 3 {
+		t.Errorf("Func(endless) stopped after 3: got %v calls want 3", calls)
+	}
+}
+
+func TestPages(t *testing.T) {
+	pages := map[string][]int{"": {1, 2}, "p2": {3}, "p3": {4, 5}}
+	nexts := map[string]string{"": "p2", "p2": "p3"}
+	var fetched []string
+	fetch := func(_ context.Context, cursor string) ([]int, string, bool, error) {
+		fetched = append(fetched, cursor)
+		items, ok := pages[cursor]
+		if !ok {
+			return nil, "", false, errors.New("unknown cursor")
+		}
+		next, ok := nexts[cursor]
+		return items, next, !ok, nil
+	}
+
+	var got []int
+	for v, err := range from.Pages(context.Background(), fetch) {
+		if err != nil {
+			t.Fatalf("Pages: got err %v want nil", err)
+		}
+		got = append(got, v)
+	}
+	if diff := cmp.Diff([]int{1, 2, 3, 4, 5}, got); diff != "" {
+		t.Errorf("Pages: got %v diff:\n%v", got, diff)
+	}
+
+	fetched = nil
+	for v := range from.Pages(context.Background(), fetch) {
+		if v == 2 {
+			break
+		}
+	}
+	if diff := cmp.Diff([]string{""}, fetched); diff != "" {
+		t.Errorf("Pages stopped on first page: fetched %q diff:\n%v", fetched, diff)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var errs []error
+	for v, err := range from.Pages(ctx, fetch) {
+		if v == 2 {
+			cancel()
+		}
+		errs = append(errs, err)
+	}
+	if last := errs[len(errs)-1]; !errors.Is(last, context.Canceled) || len(errs) != 3 {
+		t.Errorf("Pages cancelled on first page: got errs %v want [nil nil %v]", errs, context.Canceled)
+	}
+}
+
+func TestTimeRange(t *testing.T) {
+	start := time.Date(2024, 11, 8, 10, 0, 0, 0, time.UTC)
+	at := func(minutes int) time.Time { return start.Add(time.Duration(minutes) * time.Minute) }
+	tests := []struct {
+		end  time.Time
+		step time.Duration
+		want []time.Time
+	}{
+		{at(60), 15 * time.Minute, []time.Time{at(0), at(15), at(30), at(45)}},
+		{at(50), 15 * time.Minute, []time.Time{at(0), at(15), at(30), at(45)}},
+		{at(0), time.Minute, nil},
+		{at(-10), time.Minute, nil},
+		{at(10), 0, nil},
+	}
+	for _, tt := range tests {
+		got := slices.Collect(from.TimeRange(start, tt.end, tt.step))
+		if diff := cmp.Diff(tt.want, got); diff != "" {
+			t.Errorf("TimeRange(%v, %v, %v): got %v want %v diff:\n%v", start, tt.end, tt.step, got, tt.want, diff)
+		}
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	ms := time.Millisecond
+	tests := []struct {
+		base, max time.Duration
+		factor    float64
+		want      []time.Duration
+	}{
+		{100 * ms, time.Second, 2, []time.Duration{100 * ms, 200 * ms, 400 * ms, 800 * ms, 1000 * ms, 1000 * ms}},
+		{100 * ms, 100 * ms, 3, []time.Duration{100 * ms, 100 * ms, 100 * ms}},
+		{ms, ms * 10, 1, []time.Duration{ms, ms, ms}},
+		{time.Duration(1) << 61, math.MaxInt64, 2, []time.Duration{1 << 61, 1 << 62, math.MaxInt64, math.MaxInt64}},
+	}
+	for _, tt := range tests {
+		got := slices.Collect(itertools.TakeN(from.Backoff(tt.base, tt.max, tt.factor, nil), len(tt.want)))
+		if diff := cmp.Diff(tt.want, got); diff != "" {
+			t.Errorf("Backoff(%v, %v, %v): got %v want %v diff:\n%v", tt.base, tt.max, tt.factor, got, tt.want, diff)
+		}
+
+		r := rand.New(rand.NewPCG(1, 2))
+		jittered := slices.Collect(itertools.TakeN(from.Backoff(tt.base, tt.max, tt.factor, r), len(tt.want)))
+		for i, d := range jittered {
+			if d < 0 || d > tt.want[i] {
+				t.Errorf("Backoff(%v, %v, %v, jitter): got %v at step %v want in [0, %v]", tt.base, tt.max, tt.factor, d, i, tt.want[i])
+			}
+		}
+		again := slices.Collect(itertools.TakeN(from.Backoff(tt.base, tt.max, tt.factor, rand.New(rand.NewPCG(1, 2))), len(tt.want)))
+		if diff := cmp.Diff(jittered, again); diff != "" {
+			t.Errorf("Backoff(%v, %v, %v, jitter) with same seed: got different delays diff:\n%v", tt.base, tt.max, tt.factor, diff)
+		}
+	}
+
+	invalid := []struct {
+		base, max time.Duration
+		factor    float64
+	}{
+		{0, time.Second, 2},
+		{-ms, time.Second, 2},
+		{time.Second, ms, 2},
+		{ms, time.Second, 0.5},
+		{ms, time.Second, math.NaN()},
+	}
+	for _, tt := range invalid {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("Backoff(%v, %v, %v): got no panic, want panic", tt.base, tt.max, tt.factor)
+				}
+			}()
+			from.Backoff(tt.base, tt.max, tt.factor, nil)
+		}()
+	}
+}
+
+func TestIPRange(t *testing.T) {
+	tests := []struct {
+		prefix string
+		want   []string
+	}{
+		{"10.0.0.0/30", []string{"10.0.0.0", "10.0.0.1", "10.0.0.2", "10.0.0.3"}},
+		{"10.0.0.5/30", []string{"10.0.0.4", "10.0.0.5", "10.0.0.6", "10.0.0.7"}},
+		{"255.255.255.254/31", []string{"255.255.255.254", "255.255.255.255"}},
+		{"2001:db8::/127", []string{"2001:db8::", "2001:db8::1"}},
+		{"192.168.1.1/32", []string{"192.168.1.1"}},
+	}
+	for _, tt := range tests {
+		var got []string
+		for a := range from.IPRange(netip.MustParsePrefix(tt.prefix)) {
+			got = append(got, a.String())
+		}
+		if diff := cmp.Diff(tt.want, got); diff != "" {
+			t.Errorf("IPRange(%v): got %v want %v diff:\n%v", tt.prefix, got, tt.want, diff)
+		}
+	}
+	if got := slices.Collect(from.IPRange(netip.Prefix{})); len(got) != 0 {
+		t.Errorf("IPRange(invalid): got %v want none", got)
+	}
+
+	var n int
+	for range from.IPRange(netip.MustParsePrefix("10.0.0.0/8")) {
+		n++
+		if n == 10 {
+			break
+		}
+	}
+	if n != 10 {
+		t.Errorf("IPRange(10.0.0.0/8) stopped early: got %v addresses want 10", n)
+	}
+}
+
+func TestAddrRange(t *testing.T) {
+	tests := []struct {
+		first, last string
+		want        []string
+	}{
+		{"10.0.0.254", "10.0.1.1", []string{"10.0.0.254", "10.0.0.255", "10.0.1.0", "10.0.1.1"}},
+		{"10.0.0.1", "10.0.0.1", []string{"10.0.0.1"}},
+		{"10.0.0.2", "10.0.0.1", nil},
+		{"10.0.0.1", "::1", nil},
+		{"::fffe", "::1:0", []string{"::fffe", "::ffff", "::1:0"}},
+		{"255.255.255.255", "255.255.255.255", []string{"255.255.255.255"}},
+	}
+	for _, tt := range tests {
+		var got []string
+		for a := range from.AddrRange(netip.MustParseAddr(tt.first), netip.MustParseAddr(tt.last)) {
+			got = append(got, a.String())
+		}
+		if diff := cmp.Diff(tt.want, got); diff != "" {
+			t.Errorf("AddrRange(%v, %v): got %v want %v diff:\n%v", tt.first, tt.last, got, tt.want, diff)
+		}
+	}
+}
+
 func TestChan(t *testing.T) {
 	t.Run("values are emitted", func(t *testing.T) {
 		src := []int{1, 2, 3, 4}
@@ -66,6 +607,107 @@ func TestChan(t *testing.T) {
 	})
 }
 
+func TestChanDrain(t *testing.T) {
+	t.Run("values are emitted", func(t *testing.T) {
+		src := []int{1, 2, 3, 4}
+		srcc := make(chan int)
+		go func() {
+			for _, v := range src {
+				srcc <- v
+			}
+			close(srcc)
+		}()
+		got := slices.Collect(from.ChanDrain(context.Background(), srcc))
+		if diff := cmp.Diff(src, got); diff != "" {
+			t.Errorf("ChanDrain(%v): got %v want %v diff:\n%v", src, got, src, diff)
+		}
+	})
+	for _, cancelled := range []bool{false, true} {
+		t.Run(fmt.Sprintf("producer is unblocked, cancelled: %v", cancelled), func(t *testing.T) {
+			srcc := make(chan int)
+			done := make(chan struct{})
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			go func() {
+				defer close(done)
+				for i := range 10 {
+					srcc <- i
+				}
+				close(srcc)
+			}()
+			for v := range from.ChanDrain(ctx, srcc) {
+				if v != 2 {
+					continue
+				}
+				if !cancelled {
+					break
+				}
+				cancel()
+			}
+			select {
+			case <-done:
+			case <-time.After(time.Second):
+				t.Errorf("ChanDrain stopped early: producer still blocked after 1s")
+			}
+		})
+	}
+}
+
+func TestMergeChans(t *testing.T) {
+	t.Run("values are emitted", func(t *testing.T) {
+		a, b := make(chan int), make(chan int, 2)
+		go func() {
+			for _, v := range []int{1, 2, 3} {
+				a <- v
+			}
+			close(a)
+		}()
+		b <- 10
+		b <- 20
+		close(b)
+		got := slices.Sorted(from.MergeChans(context.Background(), a, b))
+		want := []int{1, 2, 3, 10, 20}
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Errorf("MergeChans(1 2 3, 10 20): got %v want %v diff:\n%v", got, want, diff)
+		}
+	})
+	t.Run("nil interface values", func(t *testing.T) {
+		errBad := errors.New("bad")
+		a, b := make(chan error, 1), make(chan error, 1)
+		a <- nil
+		b <- errBad
+		close(a)
+		close(b)
+		var got []error
+		for err := range from.MergeChans(context.Background(), a, b) {
+			got = append(got, err)
+		}
+		if len(got) != 2 || !slices.Contains(got, nil) || !slices.Contains(got, errBad) {
+			t.Errorf("MergeChans(nil, bad): got %v want [<nil> %v] in any order", got, errBad)
+		}
+	})
+	t.Run("no channels", func(t *testing.T) {
+		if got := slices.Collect(from.MergeChans[int](context.Background())); len(got) != 0 {
+			t.Errorf("MergeChans(): got %v want none", got)
+		}
+	})
+	t.Run("cancellation is handled", func(t *testing.T) {
+		a, b := make(chan int), make(chan int)
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		go func() {
+			a <- 1
+			b <- 2
+			cancel()
+		}()
+		got := slices.Sorted(from.MergeChans(ctx, a, b))
+		want := []int{1, 2}
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Errorf("MergeChans(1, 2 CANCELLED): got %v want %v diff:\n%v", got, want, diff)
+		}
+	})
+}
+
 func TestDirWalk(t *testing.T) {
 	fsys := fstest.MapFS(map[string]*fstest.MapFile{
 		"root/foo/bar.txt": {Data: []byte("hello bar")},
@@ -91,6 +733,141 @@ func TestDirWalk(t *testing.T) {
 	}
 }
 
+func TestDirWalkOptions(t *testing.T) {
+	fsys := fstest.MapFS{
+		"root/a.txt":            {},
+		"root/b.go":             {},
+		"root/skip/c.txt":       {},
+		"root/sub/d.txt":        {},
+		"root/sub/deep/e.txt":   {},
+		"root/vendor/f.txt":     {},
+		"root/sub/deep/g.go":    {},
+		"root/sub/deep/more/h":  {},
+		"root/sub/deep/.hidden": {},
+	}
+	tests := []struct {
+		name string
+		opts []from.DirWalkOption
+		want []string
+	}{
+		{
+			name: "max depth",
+			opts: []from.DirWalkOption{from.WithMaxDepth(1)},
+			want: []string{"root", "root/a.txt", "root/b.go", "root/skip", "root/sub", "root/vendor"},
+		},
+		{
+			name: "include",
+			opts: []from.DirWalkOption{from.WithInclude("*.go")},
+			want: []string{"root", "root/b.go", "root/skip", "root/sub", "root/sub/deep", "root/sub/deep/g.go", "root/sub/deep/more", "root/vendor"},
+		},
+		{
+			name: "exclude",
+			opts: []from.DirWalkOption{from.WithExclude("vendor", "sub", "*.txt")},
+			want: []string{"root", "root/b.go", "root/skip"},
+		},
+		{
+			name: "combined",
+			opts: []from.DirWalkOption{from.WithMaxDepth(3), from.WithInclude("*.txt"), from.WithExclude(".*", "vendor")},
+			want: []string{"root", "root/a.txt", "root/skip", "root/skip/c.txt", "root/sub", "root/sub/d.txt", "root/sub/deep", "root/sub/deep/e.txt", "root/sub/deep/more"},
+		},
+	}
+	for _, tt := range tests {
+		var got []string
+		for ds, err := range from.DirWalk(context.Background(), fsys, "root", tt.opts...) {
+			if err != nil {
+				t.Fatalf("DirWalk(%v): got err %v want nil", tt.name, err)
+			}
+			got = append(got, ds.Path)
+		}
+		if diff := cmp.Diff(tt.want, got); diff != "" {
+			t.Errorf("DirWalk(%v): got %v want %v diff:\n%v", tt.name, got, tt.want, diff)
+		}
+	}
+
+	t.Run("skip dir", func(t *testing.T) {
+		var got []string
+		for ds, err := range from.DirWalk(context.Background(), fsys, ".") {
+			if err != nil {
+				t.Fatalf("DirWalk(.): got err %v want nil", err)
+			}
+			if ds.Path == "root/skip" || ds.Path == "root/sub/deep" {
+				ds.SkipDir()
+			}
+			if ds.Path == "root/a.txt" {
+				// No effect on files.
+				ds.SkipDir()
+			}
+			got = append(got, ds.Path)
+		}
+		want := []string{".", "root", "root/a.txt", "root/b.go", "root/skip", "root/sub", "root/sub/d.txt", "root/sub/deep", "root/vendor", "root/vendor/f.txt"}
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Errorf("DirWalk(.) with SkipDir: got %v want %v diff:\n%v", got, want, diff)
+		}
+	})
+
+	t.Run("bad pattern", func(t *testing.T) {
+		for _, err := range from.DirWalk(context.Background(), fsys, "root", from.WithExclude("[")) {
+			if !errors.Is(err, path.ErrBadPattern) {
+				t.Errorf("DirWalk(WithExclude([)): got err %v want %v", err, path.ErrBadPattern)
+			}
+		}
+	})
+}
+
+// readDirRecorder records the directories that are read.
+type readDirRecorder struct {
+	fstest.MapFS
+	read []string
+}
+
+func (r *readDirRecorder) ReadDir(name string) ([]fs.DirEntry, error) {
+	r.read = append(r.read, name)
+	return r.MapFS.ReadDir(name)
+}
+
+func TestGlob(t *testing.T) {
+	fsys := &readDirRecorder{MapFS: fstest.MapFS{
+		"a/x/one.txt":     {},
+		"a/x/two.go":      {},
+		"a/y/three.txt":   {},
+		"a/y/deep/no.txt": {},
+		"b/x/four.txt":    {},
+		"top.txt":         {},
+	}}
+	tests := []struct {
+		pattern  string
+		want     []string
+		wantRead []string
+	}{
+		{"*.txt", []string{"top.txt"}, []string{"."}},
+		{"a/*/*.txt", []string{"a/x/one.txt", "a/y/three.txt"}, []string{".", "a", "a/x", "a/y"}},
+		{"*/x", []string{"a/x", "b/x"}, []string{".", "a", "b"}},
+		{"c/*", nil, []string{"."}},
+	}
+	for _, tt := range tests {
+		fsys.read = nil
+		var got []string
+		for p, err := range from.Glob(fsys, tt.pattern) {
+			if err != nil {
+				t.Fatalf("Glob(%q): got err %v want nil", tt.pattern, err)
+			}
+			got = append(got, p)
+		}
+		if diff := cmp.Diff(tt.want, got); diff != "" {
+			t.Errorf("Glob(%q): got %v want %v diff:\n%v", tt.pattern, got, tt.want, diff)
+		}
+		if diff := cmp.Diff(tt.wantRead, fsys.read); diff != "" {
+			t.Errorf("Glob(%q): read dirs %v want %v diff:\n%v", tt.pattern, fsys.read, tt.wantRead, diff)
+		}
+	}
+
+	for _, err := range from.Glob(fsys, "[") {
+		if !errors.Is(err, path.ErrBadPattern) {
+			t.Errorf("Glob([): got err %v want %v", err, path.ErrBadPattern)
+		}
+	}
+}
+
 func TestDirWalkCancellation(t *testing.T) {
 	fsys := fstest.MapFS(map[string]*fstest.MapFile{
 		"root/foo/bar.txt": {Data: []byte("hello bar")},
@@ -113,3 +890,196 @@ func TestDirWalkCancellation(t *testing.T) {
 		t.Errorf("DirWalk interruption: got err nil, wanted err")
 	}
 }
+
+func TestWatchDir(t *testing.T) {
+	dir := t.TempDir()
+	staging := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "b.txt"), []byte("b"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		// Create the file atomically to avoid observing it half written.
+		os.WriteFile(filepath.Join(staging, "a.txt"), []byte("a"), 0o600)
+		os.Rename(filepath.Join(staging, "a.txt"), filepath.Join(dir, "a.txt"))
+	}()
+
+	var got []string
+	var errs []error
+	for e, err := range from.WatchDir(ctx, os.DirFS(dir), ".", 5*time.Millisecond) {
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		got = append(got, e.Path+" "+e.Op.String())
+		switch {
+		case e.Path == "a.txt" && e.Op == from.Created:
+			if err := os.WriteFile(filepath.Join(dir, "a.txt"), []byte("aaa"), 0o600); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.Remove(filepath.Join(dir, "b.txt")); err != nil {
+				t.Fatal(err)
+			}
+		case e.Path == "b.txt" && e.Op == from.Removed:
+			cancel()
+		}
+	}
+	want := []string{"a.txt created", "a.txt modified", "b.txt removed"}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("WatchDir: got %v want %v diff:\n%v", got, want, diff)
+	}
+	if len(errs) != 1 || !errors.Is(errs[0], context.Canceled) {
+		t.Errorf("WatchDir: got errs %v want [%v]", errs, context.Canceled)
+	}
+
+	t.Run("invalid interval", func(t *testing.T) {
+		var n int
+		for _, err := range from.WatchDir(context.Background(), os.DirFS(dir), ".", 0) {
+			n++
+			if err == nil {
+				t.Errorf("WatchDir(interval 0): got nil err, want err")
+			}
+		}
+		if n != 1 {
+			t.Errorf("WatchDir(interval 0): got %v pairs, want 1", n)
+		}
+	})
+}
+
+func TestReaderAt(t *testing.T) {
+	src := "Hello, World!"
+	r := strings.NewReader(src)
+
+	var got []from.Chunk
+	for c, err := range from.ReaderAt(r, int64(len(src)), 5) {
+		if err != nil {
+			t.Fatalf("ReaderAt(%q, 5): got err %v want nil", src, err)
+		}
+		got = append(got, c)
+	}
+	want := []from.Chunk{
+		{Offset: 0, Data: []byte("Hello")},
+		{Offset: 5, Data: []byte(", Wor")},
+		{Offset: 10, Data: []byte("ld!")},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("ReaderAt(%q, 5): got %v want %v diff:\n%v", src, got, want, diff)
+	}
+}
+
+func TestReaderAtErrors(t *testing.T) {
+	src := "short"
+	r := strings.NewReader(src)
+
+	var errs []error
+	for _, err := range from.ReaderAt(r, 20, 5) {
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) == 0 {
+		t.Errorf("ReaderAt(%q) with size past the end: got no errors, want some", src)
+	}
+}
+
+func TestReaderChunks(t *testing.T) {
+	tests := []struct {
+		src  string
+		size int
+		want []string
+	}{
+		{"Hello, World!", 5, []string{"Hello", ", Wor", "ld!"}},
+		{"Hello", 5, []string{"Hello"}},
+		{"", 5, nil},
+	}
+	for _, tt := range tests {
+		var got, gotBuf []string
+		var retained [][]byte
+		for b, err := range from.ReaderChunks(strings.NewReader(tt.src), tt.size) {
+			if err != nil {
+				t.Fatalf("ReaderChunks(%q, %v): got err %v want nil", tt.src, tt.size, err)
+			}
+			got = append(got, string(b))
+			retained = append(retained, b)
+		}
+		for b, err := range from.ReaderChunksBuffer(strings.NewReader(tt.src), make([]byte, tt.size)) {
+			if err != nil {
+				t.Fatalf("ReaderChunksBuffer(%q, %v): got err %v want nil", tt.src, tt.size, err)
+			}
+			gotBuf = append(gotBuf, string(b))
+		}
+		if diff := cmp.Diff(tt.want, got); diff != "" {
+			t.Errorf("ReaderChunks(%q, %v): got %q want %q diff:\n%v", tt.src, tt.size, got, tt.want, diff)
+		}
+		if diff := cmp.Diff(tt.want, gotBuf); diff != "" {
+			t.Errorf("ReaderChunksBuffer(%q, %v): got %q want %q diff:\n%v", tt.src, tt.size, gotBuf, tt.want, diff)
+		}
+		for i, b := range retained {
+			if string(b) != tt.want[i] {
+				t.Errorf("ReaderChunks(%q, %v): retained chunk %v changed to %q", tt.src, tt.size, i, b)
+			}
+		}
+	}
+
+	t.Run("errors", func(t *testing.T) {
+		for _, err := range from.ReaderChunks(strings.NewReader("foo"), 0) {
+			if err == nil {
+				t.Errorf("ReaderChunks(foo, 0): got nil err, want error")
+			}
+		}
+		readErr := errors.New("read failed")
+		r := io.MultiReader(strings.NewReader("foo"), iotest.ErrReader(readErr))
+		var got []string
+		var gotErr error
+		for b, err := range from.ReaderChunks(r, 2) {
+			got = append(got, string(b))
+			gotErr = err
+		}
+		want := []string{"fo", "o"}
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Errorf("ReaderChunks(foo error, 2): got %q want %q diff:\n%v", got, want, diff)
+		}
+		if !errors.Is(gotErr, readErr) {
+			t.Errorf("ReaderChunks(foo error, 2): got err %v want %v", gotErr, readErr)
+		}
+	})
+}
+
+func TestPeekable(t *testing.T) {
+	t.Run("values are preserved", func(t *testing.T) {
+		src := []int{1, 2, 3}
+		p := from.Peekable(slices.Values(src))
+		if !p.HasNext() {
+			t.Fatalf("Peekable(%v).HasNext(): got false want true", src)
+		}
+		if got := p.Peek(); got != 1 {
+			t.Errorf("Peekable(%v).Peek(): got %v want 1", src, got)
+		}
+		got := slices.Collect(p.Seq())
+		if diff := cmp.Diff(src, got); diff != "" {
+			t.Errorf("Peekable(%v).Seq(): got %v want %v diff:\n%v", src, got, src, diff)
+		}
+		if p.HasNext() {
+			t.Errorf("Peekable(%v).HasNext() after Seq: got true want false", src)
+		}
+	})
+	t.Run("empty source", func(t *testing.T) {
+		p := from.Peekable(slices.Values([]int(nil)))
+		if p.HasNext() {
+			t.Errorf("Peekable(nil).HasNext(): got true want false")
+		}
+		if got := p.Peek(); got != 0 {
+			t.Errorf("Peekable(nil).Peek(): got %v want 0", got)
+		}
+	})
+	t.Run("stop", func(t *testing.T) {
+		p := from.Peekable(slices.Values([]int{1, 2, 3}))
+		p.HasNext()
+		p.Stop()
+		if got := slices.Collect(p.Seq()); len(got) != 0 {
+			t.Errorf("Peekable(1->3).Seq() after Stop: got %v want none", got)
+		}
+	})
+}
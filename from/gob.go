@@ -0,0 +1,26 @@
+package from
+
+import (
+	"encoding/gob"
+	"io"
+	"iter"
+)
+
+// Gob emits the values decoded from dec until the end of the stream.
+//
+// Decoding errors are emitted and stop the iteration, as the decoder cannot recover
+// from them.
+func Gob[T any](dec *gob.Decoder) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		for {
+			var t T
+			err := dec.Decode(&t)
+			if err == io.EOF {
+				return
+			}
+			if !yield(t, err) || err != nil {
+				return
+			}
+		}
+	}
+}
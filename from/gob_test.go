@@ -0,0 +1,49 @@
+package from_test
+
+import (
+	"bytes"
+	"encoding/gob"
+	"testing"
+
+	"github.com/empijei/itertools/from"
+	"github.com/google/go-cmp/cmp"
+)
+
+type gobRecord struct {
+	Name string
+	Age  int
+}
+
+func TestGob(t *testing.T) {
+	want := []gobRecord{{"Alice", 30}, {"Bob", 25}}
+	var buf bytes.Buffer
+	enc := gob.NewEncoder(&buf)
+	for _, r := range want {
+		if err := enc.Encode(r); err != nil {
+			t.Fatalf("Encode(%v): %v", r, err)
+		}
+	}
+	encoded := buf.Bytes()
+
+	var got []gobRecord
+	for r, err := range from.Gob[gobRecord](gob.NewDecoder(bytes.NewReader(encoded))) {
+		if err != nil {
+			t.Fatalf("Gob: got err %v want nil", err)
+		}
+		got = append(got, r)
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Gob(%v): got %v want %v diff:\n%v", want, got, want, diff)
+	}
+
+	truncated := encoded[:len(encoded)-3]
+	var errs int
+	for _, err := range from.Gob[gobRecord](gob.NewDecoder(bytes.NewReader(truncated))) {
+		if err != nil {
+			errs++
+		}
+	}
+	if errs != 1 {
+		t.Errorf("Gob(truncated stream): got %v errors want 1", errs)
+	}
+}
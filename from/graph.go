@@ -0,0 +1,101 @@
+package from
+
+import "iter"
+
+// TreeDFS emits root and all its descendants in depth-first pre-order, visiting the
+// children of every node in the order they are emitted by children.
+//
+// Children are only requested when the traversal reaches them, and the only memory
+// used is the recursion stack, proportional to the depth of the tree.
+func TreeDFS[T any](root T, children func(T) iter.Seq[T]) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		var walk func(T) bool
+		walk = func(n T) bool {
+			if !yield(n) {
+				return false
+			}
+			for c := range children(n) {
+				if !walk(c) {
+					return false
+				}
+			}
+			return true
+		}
+		walk(root)
+	}
+}
+
+// TreeBFS emits root and all its descendants in breadth-first order, level by level.
+//
+// Nodes are queued as their parent is emitted, so memory usage is proportional to the
+// width of the tree.
+func TreeBFS[T any](root T, children func(T) iter.Seq[T]) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		queue := []T{root}
+		for len(queue) > 0 {
+			n := queue[0]
+			queue[0] = zero[T]()
+			queue = queue[1:]
+			if !yield(n) {
+				return
+			}
+			for c := range children(n) {
+				queue = append(queue, c)
+			}
+		}
+	}
+}
+
+// GraphBFS emits start and all the nodes reachable from it in breadth-first order.
+// Every node is emitted once, even if the graph has cycles.
+//
+// Memory usage is proportional to the number of reachable nodes, as all visited nodes
+// are tracked.
+func GraphBFS[N comparable](start N, neighbors func(N) iter.Seq[N]) iter.Seq[N] {
+	return func(yield func(N) bool) {
+		visited := map[N]empty{start: {}}
+		queue := []N{start}
+		for len(queue) > 0 {
+			n := queue[0]
+			queue = queue[1:]
+			if !yield(n) {
+				return
+			}
+			for m := range neighbors(n) {
+				if _, ok := visited[m]; ok {
+					continue
+				}
+				visited[m] = empty{}
+				queue = append(queue, m)
+			}
+		}
+	}
+}
+
+// GraphDFS emits start and all the nodes reachable from it in depth-first pre-order.
+// Every node is emitted once, even if the graph has cycles.
+//
+// Memory usage is proportional to the number of reachable nodes, as all visited nodes
+// are tracked.
+func GraphDFS[N comparable](start N, neighbors func(N) iter.Seq[N]) iter.Seq[N] {
+	return func(yield func(N) bool) {
+		visited := map[N]empty{}
+		var walk func(N) bool
+		walk = func(n N) bool {
+			visited[n] = empty{}
+			if !yield(n) {
+				return false
+			}
+			for m := range neighbors(n) {
+				if _, ok := visited[m]; ok {
+					continue
+				}
+				if !walk(m) {
+					return false
+				}
+			}
+			return true
+		}
+		walk(start)
+	}
+}
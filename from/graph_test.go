@@ -0,0 +1,92 @@
+package from_test
+
+import (
+	"iter"
+	"slices"
+	"testing"
+
+	"github.com/empijei/itertools"
+	"github.com/empijei/itertools/from"
+	"github.com/google/go-cmp/cmp"
+)
+
+type treeNode struct {
+	name     string
+	children []*treeNode
+}
+
+func treeChildren(n *treeNode) iter.Seq[*treeNode] { return slices.Values(n.children) }
+
+func treeNames(src iter.Seq[*treeNode]) []string {
+	var names []string
+	for n := range src {
+		names = append(names, n.name)
+	}
+	return names
+}
+
+// testTree has root a, with children b and c. b has children d and e, c has child f.
+var testTree = &treeNode{"a", []*treeNode{
+	{"b", []*treeNode{{"d", nil}, {"e", nil}}},
+	{"c", []*treeNode{{"f", nil}}},
+}}
+
+func TestTreeDFS(t *testing.T) {
+	got := treeNames(from.TreeDFS(testTree, treeChildren))
+	want := []string{"a", "b", "d", "e", "c", "f"}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("TreeDFS: got %v want %v diff:\n%v", got, want, diff)
+	}
+	stopped := treeNames(itertools.TakeN(from.TreeDFS(testTree, treeChildren), 3))
+	if diff := cmp.Diff(want[:3], stopped); diff != "" {
+		t.Errorf("TreeDFS stopped after 3: got %v want %v diff:\n%v", stopped, want[:3], diff)
+	}
+}
+
+func TestTreeBFS(t *testing.T) {
+	got := treeNames(from.TreeBFS(testTree, treeChildren))
+	want := []string{"a", "b", "c", "d", "e", "f"}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("TreeBFS: got %v want %v diff:\n%v", got, want, diff)
+	}
+	stopped := treeNames(itertools.TakeN(from.TreeBFS(testTree, treeChildren), 4))
+	if diff := cmp.Diff(want[:4], stopped); diff != "" {
+		t.Errorf("TreeBFS stopped after 4: got %v want %v diff:\n%v", stopped, want[:4], diff)
+	}
+}
+
+// testGraph has a cycle a -> b -> c -> a and a diamond a -> d -> e, b -> e.
+var testGraph = map[string][]string{
+	"a": {"b", "d"},
+	"b": {"c", "e"},
+	"c": {"a"},
+	"d": {"e"},
+	"e": nil,
+	"z": {"a"},
+}
+
+func graphNeighbors(n string) iter.Seq[string] { return slices.Values(testGraph[n]) }
+
+func TestGraphBFS(t *testing.T) {
+	got := slices.Collect(from.GraphBFS("a", graphNeighbors))
+	want := []string{"a", "b", "d", "c", "e"}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("GraphBFS(a): got %v want %v diff:\n%v", got, want, diff)
+	}
+	got = slices.Collect(itertools.TakeN(from.GraphBFS("a", graphNeighbors), 2))
+	if diff := cmp.Diff(want[:2], got); diff != "" {
+		t.Errorf("GraphBFS(a) stopped after 2: got %v want %v diff:\n%v", got, want[:2], diff)
+	}
+}
+
+func TestGraphDFS(t *testing.T) {
+	got := slices.Collect(from.GraphDFS("a", graphNeighbors))
+	want := []string{"a", "b", "c", "e", "d"}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("GraphDFS(a): got %v want %v diff:\n%v", got, want, diff)
+	}
+	got = slices.Collect(itertools.TakeN(from.GraphDFS("a", graphNeighbors), 3))
+	if diff := cmp.Diff(want[:3], got); diff != "" {
+		t.Errorf("GraphDFS(a) stopped after 3: got %v want %v diff:\n%v", got, want[:3], diff)
+	}
+}
@@ -0,0 +1,75 @@
+package from
+
+import (
+	"container/heap"
+	"iter"
+	"slices"
+)
+
+// HeapDrain pops and emits all the elements of h, in priority order.
+// h must already satisfy the heap invariants, e.g. after calling heap.Init.
+// Elements pushed on h during the iteration are emitted as well.
+func HeapDrain(h heap.Interface) iter.Seq[any] {
+	return func(yield func(any) bool) {
+		for h.Len() > 0 {
+			if !yield(heap.Pop(h)) {
+				return
+			}
+		}
+	}
+}
+
+// PriorityQueue is a generic heap that emits its elements in priority order.
+// The zero value is not usable, use [NewPriorityQueue] to create one.
+type PriorityQueue[T any] struct {
+	h pqHeap[T]
+}
+
+// NewPriorityQueue returns a [PriorityQueue] containing a copy of values, where
+// elements that are smaller according to cmp have higher priority.
+func NewPriorityQueue[T any](cmp func(a, b T) int, values ...T) *PriorityQueue[T] {
+	pq := &PriorityQueue[T]{pqHeap[T]{items: slices.Clone(values), cmp: cmp}}
+	heap.Init(&pq.h)
+	return pq
+}
+
+// Len returns the number of elements in the queue.
+func (pq *PriorityQueue[T]) Len() int { return len(pq.h.items) }
+
+// Push adds v to the queue.
+func (pq *PriorityQueue[T]) Push(v T) { heap.Push(&pq.h, v) }
+
+// Pop removes and returns the element with the highest priority.
+// It panics if the queue is empty.
+func (pq *PriorityQueue[T]) Pop() T { return heap.Pop(&pq.h).(T) }
+
+// Drain pops and emits all the elements of the queue, in priority order.
+// Elements pushed during the iteration are emitted as well.
+func (pq *PriorityQueue[T]) Drain() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for pq.Len() > 0 {
+			if !yield(pq.Pop()) {
+				return
+			}
+		}
+	}
+}
+
+// pqHeap implements heap.Interface for PriorityQueue.
+type pqHeap[T any] struct {
+	items []T
+	cmp   func(a, b T) int
+}
+
+func (h *pqHeap[T]) Len() int           { return len(h.items) }
+func (h *pqHeap[T]) Less(i, j int) bool { return h.cmp(h.items[i], h.items[j]) < 0 }
+func (h *pqHeap[T]) Swap(i, j int)      { h.items[i], h.items[j] = h.items[j], h.items[i] }
+func (h *pqHeap[T]) Push(x any)         { h.items = append(h.items, x.(T)) }
+
+func (h *pqHeap[T]) Pop() any {
+	last := len(h.items) - 1
+	x := h.items[last]
+	h.items[last] = zero[T]()
+	h.items = h.items[:last]
+	return x
+}
@@ -0,0 +1,82 @@
+package from_test
+
+import (
+	"container/heap"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/empijei/itertools/from"
+	"github.com/google/go-cmp/cmp"
+)
+
+// intHeap is a min-heap of ints.
+type intHeap []int
+
+func (h intHeap) Len() int           { return len(h) }
+func (h intHeap) Less(i, j int) bool { return h[i] < h[j] }
+func (h intHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+func (h *intHeap) Push(x any)        { *h = append(*h, x.(int)) }
+func (h *intHeap) Pop() any {
+	old := *h
+	x := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return x
+}
+
+func TestHeapDrain(t *testing.T) {
+	h := &intHeap{5, 2, 8, 1, 9}
+	heap.Init(h)
+	var got []int
+	for v := range from.HeapDrain(h) {
+		got = append(got, v.(int))
+		if v == 8 {
+			break
+		}
+	}
+	want := []int{1, 2, 5, 8}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("HeapDrain(5 2 8 1 9): got %v want %v diff:\n%v", got, want, diff)
+	}
+	if h.Len() != 1 {
+		t.Errorf("HeapDrain(5 2 8 1 9) stopped after 8: got %v elements left want 1", h.Len())
+	}
+}
+
+func TestPriorityQueue(t *testing.T) {
+	pq := from.NewPriorityQueue(strings.Compare, "pear", "fig", "apple")
+	pq.Push("banana")
+	if got := pq.Pop(); got != "apple" {
+		t.Errorf("Pop(): got %q want %q", got, "apple")
+	}
+	var got []string
+	for v := range pq.Drain() {
+		if v == "banana" {
+			// Elements pushed while draining are emitted too.
+			pq.Push("cherry")
+		}
+		got = append(got, v)
+	}
+	want := []string{"banana", "cherry", "fig", "pear"}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Drain(): got %v want %v diff:\n%v", got, want, diff)
+	}
+	if pq.Len() != 0 {
+		t.Errorf("Len() after Drain(): got %v want 0", pq.Len())
+	}
+
+	vals := []string{"c", "a", "b"}
+	for range from.NewPriorityQueue(strings.Compare, vals...).Drain() {
+	}
+	if want := []string{"c", "a", "b"}; !slices.Equal(vals, want) {
+		t.Errorf("NewPriorityQueue(%v...): modified values to %v", want, vals)
+	}
+
+	desc := from.NewPriorityQueue(func(a, b int) int { return b - a })
+	for _, v := range []int{3, 1, 4, 1, 5} {
+		desc.Push(v)
+	}
+	if got, want := slices.Collect(desc.Drain()), []int{5, 4, 3, 1, 1}; !slices.Equal(got, want) {
+		t.Errorf("Drain() descending: got %v want %v", got, want)
+	}
+}
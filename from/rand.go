@@ -0,0 +1,47 @@
+package from
+
+import (
+	"iter"
+	"math/rand/v2"
+)
+
+// RandInts emits n pseudo-random integers in [0, max) generated by r.
+// It panics if max is not positive.
+func RandInts(r *rand.Rand, n, max int) iter.Seq[int] {
+	return func(yield func(int) bool) {
+		for range n {
+			if !yield(r.IntN(max)) {
+				return
+			}
+		}
+	}
+}
+
+// RandFloats emits n pseudo-random floats in [0.0, 1.0) generated by r.
+func RandFloats(r *rand.Rand, n int) iter.Seq[float64] {
+	return func(yield func(float64) bool) {
+		for range n {
+			if !yield(r.Float64()) {
+				return
+			}
+		}
+	}
+}
+
+// RandPerm emits a pseudo-random permutation of the integers in [0, n) generated by r.
+// The permutation is shuffled lazily, so stopping early saves work.
+func RandPerm(r *rand.Rand, n int) iter.Seq[int] {
+	return func(yield func(int) bool) {
+		perm := make([]int, n)
+		for i := range perm {
+			perm[i] = i
+		}
+		for i := range perm {
+			j := i + r.IntN(n-i)
+			perm[i], perm[j] = perm[j], perm[i]
+			if !yield(perm[i]) {
+				return
+			}
+		}
+	}
+}
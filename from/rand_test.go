@@ -0,0 +1,58 @@
+package from_test
+
+import (
+	"math/rand/v2"
+	"slices"
+	"testing"
+
+	"github.com/empijei/itertools/from"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestRandInts(t *testing.T) {
+	got := slices.Collect(from.RandInts(rand.New(rand.NewPCG(1, 2)), 100, 10))
+	if len(got) != 100 {
+		t.Fatalf("RandInts(100, 10): got %v values want 100", len(got))
+	}
+	for _, v := range got {
+		if v < 0 || v >= 10 {
+			t.Errorf("RandInts(100, 10): got %v want value in [0, 10)", v)
+		}
+	}
+	again := slices.Collect(from.RandInts(rand.New(rand.NewPCG(1, 2)), 100, 10))
+	if diff := cmp.Diff(got, again); diff != "" {
+		t.Errorf("RandInts(100, 10) with same seed: got different values diff:\n%v", diff)
+	}
+}
+
+func TestRandFloats(t *testing.T) {
+	got := slices.Collect(from.RandFloats(rand.New(rand.NewPCG(1, 2)), 100))
+	if len(got) != 100 {
+		t.Fatalf("RandFloats(100): got %v values want 100", len(got))
+	}
+	for _, v := range got {
+		if v < 0 || v >= 1 {
+			t.Errorf("RandFloats(100): got %v want value in [0, 1)", v)
+		}
+	}
+	again := slices.Collect(from.RandFloats(rand.New(rand.NewPCG(1, 2)), 100))
+	if diff := cmp.Diff(got, again); diff != "" {
+		t.Errorf("RandFloats(100) with same seed: got different values diff:\n%v", diff)
+	}
+}
+
+func TestRandPerm(t *testing.T) {
+	for _, n := range []int{0, 1, 10} {
+		got := slices.Collect(from.RandPerm(rand.New(rand.NewPCG(1, 2)), n))
+		sorted := slices.Sorted(slices.Values(got))
+		for i, v := range sorted {
+			if v != i {
+				t.Errorf("RandPerm(%v): got %v, want a permutation of [0, %v)", n, got, n)
+				break
+			}
+		}
+		if len(got) != n {
+			t.Errorf("RandPerm(%v): got %v values want %v", n, len(got), n)
+		}
+	}
+}
@@ -0,0 +1,37 @@
+package from
+
+import (
+	"archive/tar"
+	"io"
+	"iter"
+)
+
+// TarEntry is an entry of a tar archive read by [TarEntries].
+type TarEntry struct {
+	Header *tar.Header
+	// Reader reads the content of the entry. It is only valid until the consumer asks
+	// for the next entry.
+	Reader io.Reader
+}
+
+// TarEntries emits the entries of the archive read by tr, allowing to stream their
+// content without extracting the archive.
+//
+// Read errors are emitted and stop the iteration.
+func TarEntries(tr *tar.Reader) iter.Seq2[TarEntry, error] {
+	return func(yield func(TarEntry, error) bool) {
+		for {
+			h, err := tr.Next()
+			if err == io.EOF {
+				return
+			}
+			if err != nil {
+				yield(TarEntry{}, err)
+				return
+			}
+			if !yield(TarEntry{Header: h, Reader: tr}, nil) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package from_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/empijei/itertools/from"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestTarEntries(t *testing.T) {
+	files := [][2]string{{"a.txt", "hello"}, {"dir/b.txt", "world!"}, {"empty", ""}}
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	for _, f := range files {
+		if err := tw.WriteHeader(&tar.Header{Name: f[0], Mode: 0o600, Size: int64(len(f[1]))}); err != nil {
+			t.Fatalf("WriteHeader(%v): %v", f[0], err)
+		}
+		if _, err := io.WriteString(tw, f[1]); err != nil {
+			t.Fatalf("Write(%v): %v", f[0], err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	archive := buf.Bytes()
+
+	var got [][2]string
+	for e, err := range from.TarEntries(tar.NewReader(bytes.NewReader(archive))) {
+		if err != nil {
+			t.Fatalf("TarEntries: got err %v want nil", err)
+		}
+		// Only read the first byte to check that the rest is skipped.
+		b := make([]byte, 1)
+		n, _ := e.Reader.Read(b)
+		got = append(got, [2]string{e.Header.Name, string(b[:n])})
+	}
+	want := [][2]string{{"a.txt", "h"}, {"dir/b.txt", "w"}, {"empty", ""}}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("TarEntries: got %v want %v diff:\n%v", got, want, diff)
+	}
+
+	// Cut the archive in the middle of the second header.
+	var errs int
+	for _, err := range from.TarEntries(tar.NewReader(bytes.NewReader(archive[:1100]))) {
+		if err != nil {
+			errs++
+		}
+	}
+	if errs != 1 {
+		t.Errorf("TarEntries(truncated archive): got %v errors want 1", errs)
+	}
+}
@@ -0,0 +1,60 @@
+package from
+
+import (
+	"encoding/xml"
+	"io"
+	"iter"
+)
+
+// XMLTokens emits the tokens read from d. Tokens are copied, so they can be safely
+// retained after the consumer asks for the next one.
+//
+// Read and syntax errors are emitted and stop the iteration.
+func XMLTokens(d *xml.Decoder) iter.Seq2[xml.Token, error] {
+	return func(yield func(xml.Token, error) bool) {
+		for {
+			tok, err := d.Token()
+			if err == io.EOF {
+				return
+			}
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+			if !yield(xml.CopyToken(tok), nil) {
+				return
+			}
+		}
+	}
+}
+
+// XMLElements scans d for elements named localName, at any depth, and emits each of
+// them decoded into T as xml.Decoder.DecodeElement would.
+// Matching elements nested in other matching elements are decoded as part of the outer
+// one and not emitted on their own.
+//
+// This allows to stream documents that are too large to be decoded at once.
+// Errors are emitted and stop the iteration.
+func XMLElements[T any](d *xml.Decoder, localName string) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		for {
+			tok, err := d.Token()
+			if err == io.EOF {
+				return
+			}
+			if err != nil {
+				yield(zero[T](), err)
+				return
+			}
+			se, ok := tok.(xml.StartElement)
+			if !ok || se.Name.Local != localName {
+				continue
+			}
+			var t T
+			err = d.DecodeElement(&t, &se)
+			if !yield(t, err) || err != nil {
+				return
+			}
+		}
+	}
+}
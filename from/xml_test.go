@@ -0,0 +1,80 @@
+package from_test
+
+import (
+	"encoding/xml"
+	"strings"
+	"testing"
+
+	"github.com/empijei/itertools/from"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestXMLTokens(t *testing.T) {
+	src := `<a x="1">hi<b/></a>`
+	var got []string
+	for tok, err := range from.XMLTokens(xml.NewDecoder(strings.NewReader(src))) {
+		if err != nil {
+			t.Fatalf("XMLTokens(%q): got err %v want nil", src, err)
+		}
+		switch tok := tok.(type) {
+		case xml.StartElement:
+			got = append(got, "<"+tok.Name.Local)
+		case xml.EndElement:
+			got = append(got, tok.Name.Local+">")
+		case xml.CharData:
+			got = append(got, string(tok))
+		}
+	}
+	want := []string{"<a", "hi", "<b", "b>", "a>"}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("XMLTokens(%q): got %v want %v diff:\n%v", src, got, want, diff)
+	}
+
+	src = `<a><b></a>`
+	var errs int
+	for _, err := range from.XMLTokens(xml.NewDecoder(strings.NewReader(src))) {
+		if err != nil {
+			errs++
+		}
+	}
+	if errs != 1 {
+		t.Errorf("XMLTokens(%q): got %v errors want 1", src, errs)
+	}
+}
+
+func TestXMLElements(t *testing.T) {
+	type item struct {
+		ID   int    `xml:"id,attr"`
+		Name string `xml:"name"`
+	}
+	src := `<export>
+	<meta><name>ignored</name></meta>
+	<items>
+		<item id="1"><name>foo</name></item>
+		<item id="2"><name>bar</name></item>
+	</items>
+	<item id="3"><name>baz</name></item>
+</export>`
+	var got []item
+	for it, err := range from.XMLElements[item](xml.NewDecoder(strings.NewReader(src)), "item") {
+		if err != nil {
+			t.Fatalf("XMLElements(item): got err %v want nil", err)
+		}
+		got = append(got, it)
+	}
+	want := []item{{1, "foo"}, {2, "bar"}, {3, "baz"}}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("XMLElements(item): got %v want %v diff:\n%v", got, want, diff)
+	}
+
+	src = `<items><item id="x"></item><item id="2"></item></items>`
+	var errs int
+	for _, err := range from.XMLElements[item](xml.NewDecoder(strings.NewReader(src)), "item") {
+		if err != nil {
+			errs++
+		}
+	}
+	if errs != 1 {
+		t.Errorf("XMLElements(%q): got %v errors want 1", src, errs)
+	}
+}
@@ -0,0 +1,41 @@
+// Package itertest provides utilities to test iterator operators.
+package itertest
+
+import (
+	"iter"
+	"testing"
+)
+
+// CheckConstantMemory verifies that op allocates constant memory, as required for all
+// operators in the itertools package.
+//
+// It measures the allocations performed to build and fully consume op over a source of
+// one value and over a source of n values, and reports an error on t if the latter
+// allocates more.
+//
+// Since allocations are measured globally, CheckConstantMemory must not be called
+// from parallel tests.
+func CheckConstantMemory[V any](t testing.TB, op func(iter.Seq[int]) iter.Seq[V], n int) {
+	t.Helper()
+	measure := func(size int) float64 {
+		src := count(size)
+		return testing.AllocsPerRun(10, func() {
+			for range op(src) {
+			}
+		})
+	}
+	small, large := measure(1), measure(n)
+	if large > small {
+		t.Errorf("CheckConstantMemory: got %v allocations for %v values and %v for 1 value, want constant", large, n, small)
+	}
+}
+
+func count(n int) iter.Seq[int] {
+	return func(yield func(int) bool) {
+		for i := range n {
+			if !yield(i) {
+				return
+			}
+		}
+	}
+}
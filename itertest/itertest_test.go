@@ -0,0 +1,58 @@
+package itertest_test
+
+import (
+	"fmt"
+	"iter"
+	"slices"
+	"testing"
+
+	"github.com/empijei/itertools"
+	"github.com/empijei/itertools/itertest"
+)
+
+type recordingTB struct {
+	testing.TB
+	errors []string
+}
+
+func (r *recordingTB) Helper() {}
+
+func (r *recordingTB) Errorf(format string, args ...any) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func TestCheckConstantMemory(t *testing.T) {
+	tests := []struct {
+		name     string
+		op       func(iter.Seq[int]) iter.Seq[int]
+		wantFail bool
+	}{
+		{
+			name: "Map",
+			op: func(src iter.Seq[int]) iter.Seq[int] {
+				return itertools.Map(src, func(i int) int { return i * 2 })
+			},
+		},
+		{
+			name: "TakeN",
+			op: func(src iter.Seq[int]) iter.Seq[int] {
+				return itertools.TakeN(src, 1000)
+			},
+		},
+		{
+			name: "collect",
+			op: func(src iter.Seq[int]) iter.Seq[int] {
+				return slices.Values(slices.Collect(src))
+			},
+			wantFail: true,
+		},
+	}
+
+	for _, tt := range tests {
+		tb := &recordingTB{TB: t}
+		itertest.CheckConstantMemory(tb, tt.op, 1000)
+		if got := len(tb.errors) > 0; got != tt.wantFail {
+			t.Errorf("CheckConstantMemory(%v): got failed %v want %v, errors: %v", tt.name, got, tt.wantFail, tb.errors)
+		}
+	}
+}
@@ -49,6 +49,17 @@ import "iter"
 
 type empty = struct{}
 
+// Pair is a key-value couple, as emitted by iter.Seq2.
+type Pair[K, V any] struct {
+	K K
+	V V
+}
+
+// Unpack returns the key and value of the pair.
+func (p Pair[K, V]) Unpack() (K, V) {
+	return p.K, p.V
+}
+
 /***********
 * Cropping *
 ************/
@@ -71,6 +82,23 @@ func TakeN[T any](src iter.Seq[T], n int) iter.Seq[T] {
 	}
 }
 
+// TakeN2 is like [TakeN] for iter.Seq2.
+func TakeN2[K, V any](src iter.Seq2[K, V], n int) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		next, stop := iter.Pull2(src)
+		defer stop()
+		for i := 0; i < n; i++ {
+			k, v, ok := next()
+			if !ok {
+				return
+			}
+			if !yield(k, v) {
+				return
+			}
+		}
+	}
+}
+
 // TakeWhile mirrors the source iterator while predicate returns true, and stops at the first false.
 func TakeWhile[T any](src iter.Seq[T], predicate func(T) (ok bool)) iter.Seq[T] {
 	return func(yield func(T) bool) {
@@ -85,6 +113,20 @@ func TakeWhile[T any](src iter.Seq[T], predicate func(T) (ok bool)) iter.Seq[T]
 	}
 }
 
+// TakeWhile2 is like [TakeWhile] for iter.Seq2.
+func TakeWhile2[K, V any](src iter.Seq2[K, V], predicate func(K, V) (ok bool)) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		for k, v := range src {
+			if !predicate(k, v) {
+				return
+			}
+			if !yield(k, v) {
+				return
+			}
+		}
+	}
+}
+
 // SkipN discards the first n items of the source iterator and forwards the remaining items.
 // This can be seen as a slice operation such as myIterator[n:].
 func SkipN[T any](src iter.Seq[T], n int) iter.Seq[T] {
@@ -109,6 +151,29 @@ func SkipN[T any](src iter.Seq[T], n int) iter.Seq[T] {
 	}
 }
 
+// SkipN2 is like [SkipN] for iter.Seq2.
+func SkipN2[K, V any](src iter.Seq2[K, V], n int) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		next, stop := iter.Pull2(src)
+		defer stop()
+		for range n {
+			_, _, ok := next()
+			if !ok {
+				return
+			}
+		}
+		for {
+			k, v, ok := next()
+			if !ok {
+				return
+			}
+			if !yield(k, v) {
+				return
+			}
+		}
+	}
+}
+
 // SkipUntil discards all values until predicate returns true for the first time.
 // Then it stops calling predicate and forwards the first accepted value and all the remaining ones.
 func SkipUntil[T any](src iter.Seq[T], predicate func(T) (ok bool)) iter.Seq[T] {
@@ -139,6 +204,99 @@ func SkipUntil[T any](src iter.Seq[T], predicate func(T) (ok bool)) iter.Seq[T]
 	}
 }
 
+// SkipUntil2 is like [SkipUntil] for iter.Seq2.
+func SkipUntil2[K, V any](src iter.Seq2[K, V], predicate func(K, V) (ok bool)) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		next, stop := iter.Pull2(src)
+		defer stop()
+		for {
+			k, v, ok := next()
+			if !ok {
+				return
+			}
+			if predicate(k, v) {
+				if !yield(k, v) {
+					return
+				}
+				break
+			}
+		}
+		for {
+			k, v, ok := next()
+			if !ok {
+				return
+			}
+			if !yield(k, v) {
+				return
+			}
+		}
+	}
+}
+
+// Span splits the source iterator at the first value for which predicate returns false.
+// The prefix emits all the values before it, rest emits it and all the remaining ones.
+//
+// The returned iterators share the source and can only be used once, in order:
+// rest must only be used after prefix. If prefix was not fully consumed, rest
+// discards the values that prefix didn't emit.
+// Once prefix has been used, rest must be used as well, even if just to immediately
+// stop it, to release the resources associated with the source.
+func Span[T any](src iter.Seq[T], predicate func(T) (ok bool)) (prefix, rest iter.Seq[T]) {
+	var (
+		next       func() (T, bool)
+		stop       func()
+		pending    T
+		hasPending bool
+		prefixDone bool
+	)
+	pull := func() {
+		if next == nil {
+			next, stop = iter.Pull(src)
+		}
+	}
+	prefix = func(yield func(T) bool) {
+		if prefixDone {
+			return
+		}
+		pull()
+		for {
+			t, ok := next()
+			if !ok {
+				prefixDone = true
+				return
+			}
+			if !predicate(t) {
+				pending, hasPending, prefixDone = t, true, true
+				return
+			}
+			if !yield(t) {
+				return
+			}
+		}
+	}
+	rest = func(yield func(T) bool) {
+		pull()
+		defer stop()
+		prefix(func(T) bool { return true })
+		if hasPending {
+			hasPending = false
+			if !yield(pending) {
+				return
+			}
+		}
+		for {
+			t, ok := next()
+			if !ok {
+				return
+			}
+			if !yield(t) {
+				return
+			}
+		}
+	}
+	return prefix, rest
+}
+
 /***********************
 * Plucking and packing *
 ************************/
@@ -166,6 +324,8 @@ func Values[K, V any](src iter.Seq2[K, V]) iter.Seq[V] {
 }
 
 // Entries emits couples of values that represent the key-value pairs from the source iterator.
+//
+// New code should prefer [EntriesPair], which emits a named type.
 func Entries[K, V any](src iter.Seq2[K, V]) iter.Seq[struct {
 	K K
 	V V
@@ -185,6 +345,72 @@ func Entries[K, V any](src iter.Seq2[K, V]) iter.Seq[struct {
 	}
 }
 
+// EntriesPair is like [Entries] but emits the couples as [Pair] values.
+func EntriesPair[K, V any](src iter.Seq2[K, V]) iter.Seq[Pair[K, V]] {
+	return func(yield func(Pair[K, V]) bool) {
+		for k, v := range src {
+			if !yield(Pair[K, V]{k, v}) {
+				return
+			}
+		}
+	}
+}
+
+// FromEntries emits the keys and values of the pairs emitted by the source iterator.
+// It is the inverse of [EntriesPair].
+func FromEntries[K, V any](src iter.Seq[Pair[K, V]]) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		for p := range src {
+			if !yield(p.K, p.V) {
+				return
+			}
+		}
+	}
+}
+
+// Enumerate2 emits every couple emitted by the source iterator as a [Pair], together
+// with its position, starting from 0.
+func Enumerate2[K, V any](src iter.Seq2[K, V]) iter.Seq2[int, Pair[K, V]] {
+	return func(yield func(int, Pair[K, V]) bool) {
+		i := 0
+		for k, v := range src {
+			if !yield(i, Pair[K, V]{k, v}) {
+				return
+			}
+			i++
+		}
+	}
+}
+
+// Chunk2 groups the couples emitted by the source iterator in slices of n elements.
+// The last slice may be shorter. If n is not positive no slice is emitted.
+//
+// Every slice is newly allocated, so consumers can retain it.
+func Chunk2[K, V any](src iter.Seq2[K, V], n int) iter.Seq[[]Pair[K, V]] {
+	return func(yield func([]Pair[K, V]) bool) {
+		if n <= 0 {
+			return
+		}
+		var chunk []Pair[K, V]
+		for k, v := range src {
+			if chunk == nil {
+				chunk = make([]Pair[K, V], 0, n)
+			}
+			chunk = append(chunk, Pair[K, V]{k, v})
+			if len(chunk) < n {
+				continue
+			}
+			if !yield(chunk) {
+				return
+			}
+			chunk = nil
+		}
+		if len(chunk) > 0 {
+			yield(chunk)
+		}
+	}
+}
+
 /***************
 * Transforming *
 ****************/
@@ -201,6 +427,24 @@ func Map[T, V any](src iter.Seq[T], predicate func(T) V) iter.Seq[V] {
 	}
 }
 
+// MapSkipErr is like [Map] for a transformation that can fail.
+// Values for which f fails are reported to onErr, together with the error, and are
+// not emitted.
+func MapSkipErr[T, V any](src iter.Seq[T], f func(T) (V, error), onErr func(T, error)) iter.Seq[V] {
+	return func(yield func(V) bool) {
+		for t := range src {
+			v, err := f(t)
+			if err != nil {
+				onErr(t, err)
+				continue
+			}
+			if !yield(v) {
+				return
+			}
+		}
+	}
+}
+
 // Map2 is like [Map] for iter.Seq2.
 func Map2[K1, V1, K2, V2 any](src iter.Seq2[K1, V1], predicate func(K1, V1) (K2, V2)) iter.Seq2[K2, V2] {
 	return func(yield func(K2, V2) bool) {
@@ -223,6 +467,13 @@ func Map12[T, K, V any](src iter.Seq[T], predicate func(T) (K, V)) iter.Seq2[K,
 	}
 }
 
+// With emits every value of the source iterator as the key of a couple whose value
+// is derived from it by f.
+// This is unlike [Map], which discards the source value.
+func With[T, V any](src iter.Seq[T], f func(T) V) iter.Seq2[T, V] {
+	return Map12(src, func(t T) (T, V) { return t, f(t) })
+}
+
 // Map21 is like [Map] but it transforms the iterator from Seq2 to Seq.
 func Map21[K, V, T any](src iter.Seq2[K, V], predicate func(K, V) T) iter.Seq[T] {
 	return func(yield func(T) bool) {
@@ -269,6 +520,13 @@ func EmptyValues[T any](src iter.Seq[T]) iter.Seq2[T, empty] {
 	return Map12(src, func(t T) (T, empty) { return t, empty{} })
 }
 
+// BucketBy emits every value of the source iterator as the value of a couple
+// whose key is the bucket the value belongs to.
+// This allows to filter or sample buckets before collecting them.
+func BucketBy[T any, B comparable](src iter.Seq[T], bucket func(T) B) iter.Seq2[B, T] {
+	return Map12(src, func(t T) (B, T) { return bucket(t), t })
+}
+
 // PairWise emits all values with the value that preceded them.
 // This means all values will be emitted twice except for the first and last one.
 // Values are emitted once as the second value, then as the first, in this order.
@@ -294,6 +552,37 @@ func PairWise[T any](src iter.Seq[T]) iter.Seq2[T, T] {
 	}
 }
 
+// Pairs emits the values of the source iterator two at a time, so that every value
+// is emitted exactly once. This is unlike [PairWise], which uses a sliding window.
+// If the source emits an odd number of values the trailing one is discarded, use
+// [PairsRemainder] to observe it.
+func Pairs[T any](src iter.Seq[T]) iter.Seq2[T, T] {
+	return PairsRemainder(src, func(T) {})
+}
+
+// PairsRemainder is like [Pairs], but if the source emits an odd number of values
+// the trailing one is passed to rest once the source is exhausted.
+// rest is not called if the consumer stops iteration early.
+func PairsRemainder[T any](src iter.Seq[T], rest func(T)) iter.Seq2[T, T] {
+	return func(yield func(T, T) bool) {
+		var first T
+		var half bool
+		for t := range src {
+			if !half {
+				first, half = t, true
+				continue
+			}
+			half = false
+			if !yield(first, t) {
+				return
+			}
+		}
+		if half {
+			rest(first)
+		}
+	}
+}
+
 // Zip emits every time both source iterators have emitted
 // a value, thus generating couples of values where no source value is used more than
 // once and no one is discarded except for the trailing ones after one of the sources
@@ -334,6 +623,18 @@ func Tap[T any](src iter.Seq[T], peek func(T)) iter.Seq[T] {
 	}
 }
 
+// Tap2 is like [Tap] for iter.Seq2.
+func Tap2[K, V any](src iter.Seq2[K, V], peek func(K, V)) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		for k, v := range src {
+			peek(k, v)
+			if !yield(k, v) {
+				return
+			}
+		}
+	}
+}
+
 // Deduplicate removes duplicates emitted by src. It doesn't check that
 // the entire iterator never emits two identical values, it just removes consecutive
 // identical values.
@@ -361,6 +662,121 @@ func Deduplicate[T comparable](src iter.Seq[T]) iter.Seq[T] {
 	}
 }
 
+// Deduplicate2 is like [Deduplicate] for iter.Seq2. Couples are considered identical
+// if both their keys and values are equal.
+func Deduplicate2[K, V comparable](src iter.Seq2[K, V]) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		next, stop := iter.Pull2(src)
+		defer stop()
+		prevK, prevV, ok := next()
+		if !ok || !yield(prevK, prevV) {
+			return
+		}
+		for {
+			k, v, ok := next()
+			if !ok {
+				return
+			}
+			if k == prevK && v == prevV {
+				continue
+			}
+			prevK, prevV = k, v
+			if !yield(k, v) {
+				return
+			}
+		}
+	}
+}
+
+// DeduplicateByKey collapses consecutive couples with the same key, keeping the first one.
+func DeduplicateByKey[K comparable, V any](src iter.Seq2[K, V]) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		next, stop := iter.Pull2(src)
+		defer stop()
+		prev, v, ok := next()
+		if !ok || !yield(prev, v) {
+			return
+		}
+		for {
+			k, v, ok := next()
+			if !ok {
+				return
+			}
+			if k == prev {
+				continue
+			}
+			prev = k
+			if !yield(k, v) {
+				return
+			}
+		}
+	}
+}
+
+// DeduplicateByKeyLast collapses consecutive couples with the same key, keeping the last one.
+// This is useful to compact change logs, where only the latest value for a key matters.
+func DeduplicateByKeyLast[K comparable, V any](src iter.Seq2[K, V]) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		var (
+			lastK   K
+			lastV   V
+			pending bool
+		)
+		for k, v := range src {
+			if pending && k != lastK {
+				if !yield(lastK, lastV) {
+					return
+				}
+			}
+			lastK, lastV, pending = k, v, true
+		}
+		if pending {
+			yield(lastK, lastV)
+		}
+	}
+}
+
+// DeduplicateWindowed removes values that were already emitted by src within the
+// last window items. A window of 1 is equivalent to [Deduplicate].
+// If window is not positive no value is removed.
+//
+// It allocates memory proportional to window.
+func DeduplicateWindowed[T comparable](src iter.Seq[T], window int) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		if window <= 0 {
+			for t := range src {
+				if !yield(t) {
+					return
+				}
+			}
+			return
+		}
+		ring := make([]T, 0, window)
+		seen := make(map[T]int, window)
+		var oldest int
+		for t := range src {
+			dup := seen[t] > 0
+			if len(ring) < window {
+				ring = append(ring, t)
+			} else {
+				evicted := ring[oldest]
+				if seen[evicted]--; seen[evicted] == 0 {
+					delete(seen, evicted)
+				}
+				ring[oldest] = t
+				oldest = (oldest + 1) % window
+			}
+			seen[t]++
+			if dup {
+				continue
+			}
+			if !yield(t) {
+				return
+			}
+		}
+	}
+}
+
 /***************
 * Higher order *
 ****************/
@@ -406,6 +822,124 @@ func Flatten2[K, V any](src iter.Seq2[K, iter.Seq[V]]) iter.Seq2[K, V] {
 	}
 }
 
+// Flatten22 is like [Flatten2] for inner iterators that emit couples.
+// The outer and inner keys are combined in a [Pair].
+func Flatten22[K1, K2, V any](src iter.Seq2[K1, iter.Seq2[K2, V]]) iter.Seq2[Pair[K1, K2], V] {
+	return func(yield func(Pair[K1, K2], V) bool) {
+		for k1, i := range src {
+			for k2, v := range i {
+				if !yield(Pair[K1, K2]{k1, k2}, v) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// FlattenSlice2 is like [Flatten2] for iterators of slices.
+func FlattenSlice2[K, V any](src iter.Seq2[K, []V]) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		for k, i := range src {
+			for _, v := range i {
+				if !yield(k, v) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// GroupAdjacentByKey groups consecutive couples that share the same key, and emits
+// every key once together with an iterator over the values of its group.
+// It is the inverse of [Flatten2].
+//
+// Inner iterators share the source with the outer one: they can only be used once,
+// and only until the outer iterator advances. Values that are not consumed by the
+// inner iterators are discarded.
+func GroupAdjacentByKey[K comparable, V any](src iter.Seq2[K, V]) iter.Seq2[K, iter.Seq[V]] {
+	return func(yield func(K, iter.Seq[V]) bool) {
+		next, stop := iter.Pull2(src)
+		defer stop()
+		k, v, ok := next()
+		for ok {
+			cur := k
+			valid := true
+			group := func(yield func(V) bool) {
+				for valid && ok && k == cur {
+					if !yield(v) {
+						return
+					}
+					k, v, ok = next()
+				}
+			}
+			if !yield(cur, group) {
+				return
+			}
+			valid = false
+			for ok && k == cur {
+				k, v, ok = next()
+			}
+		}
+	}
+}
+
+// ReduceByKey folds the values of consecutive couples that share the same key with f,
+// and emits one couple per run with the key and the folded value.
+// On key-sorted sources this aggregates all values for every key.
+func ReduceByKey[K comparable, V any](src iter.Seq2[K, V], f func(accum, cur V) V) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		var (
+			curK    K
+			accum   V
+			pending bool
+		)
+		for k, v := range src {
+			if pending && k == curK {
+				accum = f(accum, v)
+				continue
+			}
+			if pending && !yield(curK, accum) {
+				return
+			}
+			curK, accum, pending = k, v, true
+		}
+		if pending {
+			yield(curK, accum)
+		}
+	}
+}
+
+// FoldRuns groups consecutive values that share the same key and folds every group
+// into a single accumulator, emitting one couple per group with the key and the
+// final accumulator.
+// Accumulators are initialized with init for every group, and then updated with
+// fold for every value of the group.
+//
+// This is equivalent to grouping adjacent values and reducing every group, but it
+// doesn't need to keep the groups in memory.
+func FoldRuns[T any, K comparable, A any](src iter.Seq[T], key func(T) K, init func(K) A, fold func(A, T) A) iter.Seq2[K, A] {
+	return func(yield func(K, A) bool) {
+		var (
+			curK    K
+			accum   A
+			pending bool
+		)
+		for t := range src {
+			k := key(t)
+			if !pending || k != curK {
+				if pending && !yield(curK, accum) {
+					return
+				}
+				curK, accum, pending = k, init(k), true
+			}
+			accum = fold(accum, t)
+		}
+		if pending {
+			yield(curK, accum)
+		}
+	}
+}
+
 // Concat emits all values from the provided sources, in order.
 func Concat[T any](srcs ...iter.Seq[T]) iter.Seq[T] {
 	return func(yield func(T) bool) {
@@ -418,3 +952,57 @@ func Concat[T any](srcs ...iter.Seq[T]) iter.Seq[T] {
 		}
 	}
 }
+
+// Interleave emits one value from each source in turn, in a round-robin fashion.
+// When a source is exhausted it is skipped, and iteration continues with the
+// remaining ones until all of them are exhausted.
+func Interleave[T any](srcs ...iter.Seq[T]) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		nexts := make([]func() (T, bool), 0, len(srcs))
+		for _, src := range srcs {
+			next, stop := iter.Pull(src)
+			defer stop()
+			nexts = append(nexts, next)
+		}
+		for len(nexts) > 0 {
+			for i := 0; i < len(nexts); {
+				t, ok := nexts[i]()
+				if !ok {
+					nexts = append(nexts[:i], nexts[i+1:]...)
+					continue
+				}
+				if !yield(t) {
+					return
+				}
+				i++
+			}
+		}
+	}
+}
+
+// InterleaveShortest is like [Interleave] but stops as soon as any of the sources is exhausted.
+// This is useful to merge finite and infinite sources.
+func InterleaveShortest[T any](srcs ...iter.Seq[T]) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		if len(srcs) == 0 {
+			return
+		}
+		nexts := make([]func() (T, bool), 0, len(srcs))
+		for _, src := range srcs {
+			next, stop := iter.Pull(src)
+			defer stop()
+			nexts = append(nexts, next)
+		}
+		for {
+			for _, next := range nexts {
+				t, ok := next()
+				if !ok {
+					return
+				}
+				if !yield(t) {
+					return
+				}
+			}
+		}
+	}
+}
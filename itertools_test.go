@@ -5,6 +5,7 @@ import (
 	"iter"
 	"slices"
 	"strconv"
+	"strings"
 	"testing"
 
 	. "github.com/empijei/itertools"
@@ -141,6 +142,30 @@ func TestTakeN(t *testing.T) {
 	}
 }
 
+func TestTakeN2(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		src  []int
+		n    int
+		want [][2]int
+	}{
+		{[]int{}, 3, nil},
+		{nil, 0, nil},
+		{[]int{5, 6, 7, 8}, 2, [][2]int{{0, 5}, {1, 6}}},
+		{[]int{3, 7}, 10, [][2]int{{0, 3}, {1, 7}}},
+		{[]int{3, 7, 11}, 0, nil},
+	}
+	for _, tt := range tests {
+		var got [][2]int
+		for k, v := range TakeN2(slices.All(tt.src), tt.n) {
+			got = append(got, [2]int{k, v})
+		}
+		if diff := cmp.Diff(tt.want, got); diff != "" {
+			t.Errorf("TakeN2(%v, %v): got %v want %v diff:\n%v", tt.src, tt.n, got, tt.want, diff)
+		}
+	}
+}
+
 func TestTakeWhile(t *testing.T) {
 	t.Parallel()
 	src := []int{1, 2, 3, 4, 5, 6, 7, 8, 9}
@@ -152,6 +177,20 @@ func TestTakeWhile(t *testing.T) {
 	}
 }
 
+func TestTakeWhile2(t *testing.T) {
+	t.Parallel()
+	src := []int{1, 2, 3, 4, 5}
+	pred := func(k, v int) bool { return k+v < 5 }
+	var got [][2]int
+	for k, v := range TakeWhile2(slices.All(src), pred) {
+		got = append(got, [2]int{k, v})
+	}
+	want := [][2]int{{0, 1}, {1, 2}}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("TakeWhile2(1->5, k+v<5): got %v want %v", got, want)
+	}
+}
+
 func TestSkipN(t *testing.T) {
 	t.Parallel()
 	tests := []struct {
@@ -174,6 +213,30 @@ func TestSkipN(t *testing.T) {
 	}
 }
 
+func TestSkipN2(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		src  []int
+		n    int
+		want [][2]int
+	}{
+		{[]int{}, 3, nil},
+		{nil, 0, nil},
+		{[]int{5, 6, 7, 8}, 2, [][2]int{{2, 7}, {3, 8}}},
+		{[]int{3, 7, 11}, 10, nil},
+		{[]int{3, 7}, 0, [][2]int{{0, 3}, {1, 7}}},
+	}
+	for _, tt := range tests {
+		var got [][2]int
+		for k, v := range SkipN2(slices.All(tt.src), tt.n) {
+			got = append(got, [2]int{k, v})
+		}
+		if diff := cmp.Diff(tt.want, got); diff != "" {
+			t.Errorf("SkipN2(%v, %v): got %v want %v diff:\n%v", tt.src, tt.n, got, tt.want, diff)
+		}
+	}
+}
+
 func TestSkipUntil(t *testing.T) {
 	t.Parallel()
 	src := []int{1, 2, 3, 4, 5, 6, 7, 8, 9}
@@ -192,6 +255,78 @@ func TestSkipUntil(t *testing.T) {
 	}
 }
 
+func TestSkipUntil2(t *testing.T) {
+	t.Parallel()
+	src := []int{1, 2, 3, 4, 5}
+	var callCount int
+	pred := func(k, v int) bool {
+		callCount++
+		return k+v > 5
+	}
+	var got [][2]int
+	for k, v := range SkipUntil2(slices.All(src), pred) {
+		got = append(got, [2]int{k, v})
+	}
+	want := [][2]int{{3, 4}, {4, 5}}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("SkipUntil2(1->5, k+v>5): got %v want %v", got, want)
+	}
+	if wantCalls := 4; callCount != wantCalls {
+		t.Errorf("SkipUntil2(1->5, k+v>5): got %v calls to predicate, want %v", callCount, wantCalls)
+	}
+}
+
+func TestSpan(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name        string
+		src         []string
+		drainPrefix bool
+		wantPrefix  []string
+		wantRest    []string
+	}{
+		{
+			name:        "header and body",
+			src:         []string{"From: a", "To: b", "", "Hello", "World"},
+			drainPrefix: true,
+			wantPrefix:  []string{"From: a", "To: b"},
+			wantRest:    []string{"", "Hello", "World"},
+		},
+		{
+			name:        "prefix not consumed",
+			src:         []string{"From: a", "To: b", "", "Hello"},
+			drainPrefix: false,
+			wantRest:    []string{"", "Hello"},
+		},
+		{
+			name:        "all prefix",
+			src:         []string{"From: a", "To: b"},
+			drainPrefix: true,
+			wantPrefix:  []string{"From: a", "To: b"},
+		},
+		{
+			name:        "empty",
+			drainPrefix: true,
+		},
+	}
+	notEmpty := func(s string) bool { return s != "" }
+
+	for _, tt := range tests {
+		prefix, rest := Span(slices.Values(tt.src), notEmpty)
+		var gotPrefix []string
+		if tt.drainPrefix {
+			gotPrefix = slices.Collect(prefix)
+		}
+		gotRest := slices.Collect(rest)
+		if diff := cmp.Diff(tt.wantPrefix, gotPrefix); diff != "" {
+			t.Errorf("%v: Span(%v) prefix: got %v want %v diff:\n%v", tt.name, tt.src, gotPrefix, tt.wantPrefix, diff)
+		}
+		if diff := cmp.Diff(tt.wantRest, gotRest); diff != "" {
+			t.Errorf("%v: Span(%v) rest: got %v want %v diff:\n%v", tt.name, tt.src, gotRest, tt.wantRest, diff)
+		}
+	}
+}
+
 func TestKeys(t *testing.T) {
 	t.Parallel()
 	tests := []struct {
@@ -251,6 +386,75 @@ func TestEntries(t *testing.T) {
 	}
 }
 
+func TestEntriesPair(t *testing.T) {
+	t.Parallel()
+	src := []int{2, 3, 4, 5}
+	got := slices.Collect(EntriesPair(slices.All(src)))
+	want := []Pair[int, int]{{0, 2}, {1, 3}, {2, 4}, {3, 5}}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("EntriesPair(slices.All(%v)): got %v want %v diff:\n%v", src, got, want, diff)
+	}
+}
+
+func TestEnumerate2(t *testing.T) {
+	t.Parallel()
+	src := []strIntPair{{"a", 5}, {"b", 6}, {"a", 7}}
+	got := collectPairs(Enumerate2(FromEntries(slices.Values(src))))
+	want := []Pair[int, strIntPair]{{0, strIntPair{"a", 5}}, {1, strIntPair{"b", 6}}, {2, strIntPair{"a", 7}}}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Enumerate2(%v): got %v want %v diff:\n%v", src, got, want, diff)
+	}
+}
+
+func TestChunk2(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		src  []int
+		n    int
+		want [][]Pair[int, int]
+	}{
+		{
+			[]int{5, 6, 7, 8, 9},
+			2,
+			[][]Pair[int, int]{{{0, 5}, {1, 6}}, {{2, 7}, {3, 8}}, {{4, 9}}},
+		},
+		{
+			[]int{5, 6},
+			2,
+			[][]Pair[int, int]{{{0, 5}, {1, 6}}},
+		},
+		{[]int{5, 6}, 0, nil},
+		{nil, 3, nil},
+	}
+	for _, tt := range tests {
+		got := slices.Collect(Chunk2(slices.All(tt.src), tt.n))
+		if diff := cmp.Diff(tt.want, got); diff != "" {
+			t.Errorf("Chunk2(slices.All(%v), %v): got %v want %v diff:\n%v", tt.src, tt.n, got, tt.want, diff)
+		}
+	}
+}
+
+func TestPairUnpack(t *testing.T) {
+	t.Parallel()
+	k, v := Pair[string, int]{"a", 1}.Unpack()
+	if k != "a" || v != 1 {
+		t.Errorf("Pair{a 1}.Unpack(): got %v %v want a 1", k, v)
+	}
+}
+
+func TestFromEntries(t *testing.T) {
+	t.Parallel()
+	src := []int{2, 3, 4, 5}
+	var got [][2]int
+	for k, v := range FromEntries(EntriesPair(slices.All(src))) {
+		got = append(got, [2]int{k, v})
+	}
+	want := [][2]int{{0, 2}, {1, 3}, {2, 4}, {3, 5}}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("FromEntries(EntriesPair(slices.All(%v))): got %v want %v diff:\n%v", src, got, want, diff)
+	}
+}
+
 func TestMap(t *testing.T) {
 	t.Parallel()
 	tests := []struct {
@@ -273,6 +477,36 @@ func TestMap(t *testing.T) {
 	}
 }
 
+func TestMapSkipErr(t *testing.T) {
+	t.Parallel()
+	src := []string{"1", "two", "3", "four"}
+	var failed []string
+	got := slices.Collect(MapSkipErr(slices.Values(src), strconv.Atoi, func(s string, err error) {
+		if err == nil {
+			t.Errorf("MapSkipErr(%v, Atoi): onErr called with nil error for %q", src, s)
+		}
+		failed = append(failed, s)
+	}))
+	want := []int{1, 3}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("MapSkipErr(%v, Atoi): got %v want %v diff:\n%v", src, got, want, diff)
+	}
+	wantFailed := []string{"two", "four"}
+	if diff := cmp.Diff(wantFailed, failed); diff != "" {
+		t.Errorf("MapSkipErr(%v, Atoi): got failures %v want %v diff:\n%v", src, failed, wantFailed, diff)
+	}
+}
+
+func TestWith(t *testing.T) {
+	t.Parallel()
+	src := []string{"a", "bb", "ccc"}
+	got := collectPairs(With(slices.Values(src), func(s string) int { return len(s) }))
+	want := []strIntPair{{"a", 1}, {"bb", 2}, {"ccc", 3}}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("With(%v, len): got %v want %v diff:\n%v", src, got, want, diff)
+	}
+}
+
 func TestFilter(t *testing.T) {
 	t.Parallel()
 	tests := []struct {
@@ -318,6 +552,22 @@ func TestMapFilterHigherArity(t *testing.T) {
 	}
 }
 
+func TestBucketBy(t *testing.T) {
+	t.Parallel()
+	src := []int{1, 2, 3, 4, 5}
+	parity := func(i int) string {
+		if i%2 == 0 {
+			return "even"
+		}
+		return "odd"
+	}
+	got := collectPairs(BucketBy(slices.Values(src), parity))
+	want := []strIntPair{{"odd", 1}, {"even", 2}, {"odd", 3}, {"even", 4}, {"odd", 5}}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("BucketBy(%v, parity): got %v want %v diff:\n%v", src, got, want, diff)
+	}
+}
+
 func TestPairWise(t *testing.T) {
 	t.Parallel()
 	tests := []struct {
@@ -347,6 +597,80 @@ func TestPairWise(t *testing.T) {
 	}
 }
 
+func TestPairs(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		src  []int
+		want [][]int
+	}{
+		{
+			[]int{1, 2, 3, 4},
+			[][]int{{1, 2}, {3, 4}},
+		},
+		{
+			[]int{1, 2, 3},
+			[][]int{{1, 2}},
+		},
+		{
+			[]int{1},
+			nil,
+		},
+		{nil, nil},
+	}
+
+	for _, tt := range tests {
+		srci := slices.Values(tt.src)
+		var got [][]int
+		for a, b := range Pairs(srci) {
+			got = append(got, []int{a, b})
+		}
+		if diff := cmp.Diff(tt.want, got); diff != "" {
+			t.Errorf("Pairs(%v): got %v want %v diff:\n%v", tt.src, got, tt.want, diff)
+		}
+	}
+}
+
+func TestPairsRemainder(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		src      []int
+		want     [][]int
+		wantRest []int
+	}{
+		{
+			[]int{1, 2, 3, 4},
+			[][]int{{1, 2}, {3, 4}},
+			nil,
+		},
+		{
+			[]int{1, 2, 3},
+			[][]int{{1, 2}},
+			[]int{3},
+		},
+		{
+			[]int{1},
+			nil,
+			[]int{1},
+		},
+		{nil, nil, nil},
+	}
+
+	for _, tt := range tests {
+		srci := slices.Values(tt.src)
+		var got [][]int
+		var gotRest []int
+		for a, b := range PairsRemainder(srci, func(r int) { gotRest = append(gotRest, r) }) {
+			got = append(got, []int{a, b})
+		}
+		if diff := cmp.Diff(tt.want, got); diff != "" {
+			t.Errorf("PairsRemainder(%v): got %v want %v diff:\n%v", tt.src, got, tt.want, diff)
+		}
+		if diff := cmp.Diff(tt.wantRest, gotRest); diff != "" {
+			t.Errorf("PairsRemainder(%v) rest: got %v want %v diff:\n%v", tt.src, gotRest, tt.wantRest, diff)
+		}
+	}
+}
+
 func TestZip(t *testing.T) {
 	t.Parallel()
 	tests := []struct {
@@ -418,6 +742,21 @@ func TestTap(t *testing.T) {
 	})
 }
 
+func TestTap2(t *testing.T) {
+	t.Parallel()
+	var sumK, sumV int
+	it := Tap2(slices.All([]int{1, 2, 3, 4, 5}), func(k, v int) {
+		sumK += k
+		sumV += v
+	})
+	it(func(k, _ int) bool {
+		return k < 2
+	})
+	if sumK != 3 || sumV != 6 {
+		t.Errorf("Tap2([1 2 3 Cancelled], sumAll): got keys %v values %v want 3 6", sumK, sumV)
+	}
+}
+
 func TestDeduplicate(t *testing.T) {
 	t.Parallel()
 	tests := []struct {
@@ -447,6 +786,97 @@ func TestDeduplicate(t *testing.T) {
 	}
 }
 
+type strIntPair = Pair[string, int]
+
+func collectPairs[K, V any](src iter.Seq2[K, V]) []Pair[K, V] {
+	return slices.Collect(EntriesPair(src))
+}
+
+func TestDeduplicate2(t *testing.T) {
+	t.Parallel()
+	src := []strIntPair{{"a", 1}, {"a", 1}, {"a", 2}, {"b", 2}, {"b", 2}, {"a", 2}}
+	got := collectPairs(Deduplicate2(FromEntries(slices.Values(src))))
+	want := []strIntPair{{"a", 1}, {"a", 2}, {"b", 2}, {"a", 2}}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Deduplicate2(%v): got %v want %v diff:\n%v", src, got, want, diff)
+	}
+}
+
+func TestDeduplicateByKey(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		src       []strIntPair
+		wantFirst []strIntPair
+		wantLast  []strIntPair
+	}{
+		{
+			[]strIntPair{{"a", 1}, {"a", 2}, {"b", 3}, {"a", 4}, {"c", 5}, {"c", 6}},
+			[]strIntPair{{"a", 1}, {"b", 3}, {"a", 4}, {"c", 5}},
+			[]strIntPair{{"a", 2}, {"b", 3}, {"a", 4}, {"c", 6}},
+		},
+		{
+			[]strIntPair{{"a", 1}},
+			[]strIntPair{{"a", 1}},
+			[]strIntPair{{"a", 1}},
+		},
+		{},
+	}
+
+	for _, tt := range tests {
+		got := collectPairs(DeduplicateByKey(FromEntries(slices.Values(tt.src))))
+		if diff := cmp.Diff(tt.wantFirst, got); diff != "" {
+			t.Errorf("DeduplicateByKey(%v): got %v want %v diff:\n%v", tt.src, got, tt.wantFirst, diff)
+		}
+		got = collectPairs(DeduplicateByKeyLast(FromEntries(slices.Values(tt.src))))
+		if diff := cmp.Diff(tt.wantLast, got); diff != "" {
+			t.Errorf("DeduplicateByKeyLast(%v): got %v want %v diff:\n%v", tt.src, got, tt.wantLast, diff)
+		}
+	}
+}
+
+func TestDeduplicateWindowed(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		src    []int
+		window int
+		want   []int
+	}{
+		{
+			[]int{1, 1, 2, 1, 3, 1},
+			1,
+			[]int{1, 2, 1, 3, 1},
+		},
+		{
+			[]int{1, 1, 2, 1, 3, 1},
+			2,
+			[]int{1, 2, 3},
+		},
+		{
+			[]int{1, 2, 3, 1, 2, 4, 1},
+			2,
+			[]int{1, 2, 3, 1, 2, 4, 1},
+		},
+		{
+			[]int{1, 2, 3, 1, 2, 4, 1},
+			3,
+			[]int{1, 2, 3, 4},
+		},
+		{
+			[]int{1, 1, 1},
+			0,
+			[]int{1, 1, 1},
+		},
+		{},
+	}
+
+	for _, tt := range tests {
+		got := slices.Collect(DeduplicateWindowed(slices.Values(tt.src), tt.window))
+		if diff := cmp.Diff(tt.want, got); diff != "" {
+			t.Errorf("DeduplicateWindowed(%v, %v): got %v want %v diff:\n%v", tt.src, tt.window, got, tt.want, diff)
+		}
+	}
+}
+
 func TestFlatten(t *testing.T) {
 	t.Parallel()
 	tests := []struct {
@@ -519,6 +949,145 @@ func TestFlatten2(t *testing.T) {
 	}
 }
 
+func TestFlatten22(t *testing.T) {
+	t.Parallel()
+	src := [][]string{{"a", "b"}, nil, {"c"}}
+	outer := Map2(slices.All(src), func(k int, v []string) (int, iter.Seq2[int, string]) {
+		return k, slices.All(v)
+	})
+	got := collectPairs(Flatten22(outer))
+	want := []Pair[Pair[int, int], string]{
+		{Pair[int, int]{0, 0}, "a"},
+		{Pair[int, int]{0, 1}, "b"},
+		{Pair[int, int]{2, 0}, "c"},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Flatten22(%v): got %v want %v diff:\n%v", src, got, want, diff)
+	}
+}
+
+func TestFlattenSlice2(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		src  [][]int
+		want [][2]int
+	}{
+		{
+			[][]int{{1, 2}, nil, {3}},
+			[][2]int{{0, 1}, {0, 2}, {2, 3}},
+		},
+	}
+	for _, tt := range tests {
+		var got [][2]int
+		for k, v := range FlattenSlice2(slices.All(tt.src)) {
+			got = append(got, [2]int{k, v})
+		}
+		if diff := cmp.Diff(tt.want, got); diff != "" {
+			t.Errorf("FlattenSlice2(%v): got %v want %v diff:\n%v", tt.src, got, tt.want, diff)
+		}
+	}
+}
+
+func TestGroupAdjacentByKey(t *testing.T) {
+	t.Parallel()
+	src := []strIntPair{{"a", 1}, {"a", 2}, {"b", 3}, {"a", 4}, {"c", 5}, {"c", 6}}
+	t.Run("all groups", func(t *testing.T) {
+		t.Parallel()
+		var keys []string
+		var groups [][]int
+		for k, g := range GroupAdjacentByKey(FromEntries(slices.Values(src))) {
+			keys = append(keys, k)
+			groups = append(groups, slices.Collect(g))
+		}
+		wantKeys := []string{"a", "b", "a", "c"}
+		wantGroups := [][]int{{1, 2}, {3}, {4}, {5, 6}}
+		if diff := cmp.Diff(wantKeys, keys); diff != "" {
+			t.Errorf("GroupAdjacentByKey(%v): got keys %v want %v diff:\n%v", src, keys, wantKeys, diff)
+		}
+		if diff := cmp.Diff(wantGroups, groups); diff != "" {
+			t.Errorf("GroupAdjacentByKey(%v): got groups %v want %v diff:\n%v", src, groups, wantGroups, diff)
+		}
+	})
+	t.Run("groups partially consumed", func(t *testing.T) {
+		t.Parallel()
+		var firsts []int
+		var stale []iter.Seq[int]
+		for _, g := range GroupAdjacentByKey(FromEntries(slices.Values(src))) {
+			firsts = append(firsts, slices.Collect(TakeN(g, 1))...)
+			stale = append(stale, g)
+		}
+		want := []int{1, 3, 4, 5}
+		if diff := cmp.Diff(want, firsts); diff != "" {
+			t.Errorf("GroupAdjacentByKey(%v) first of groups: got %v want %v diff:\n%v", src, firsts, want, diff)
+		}
+		for _, g := range stale {
+			if got := slices.Collect(g); len(got) != 0 {
+				t.Errorf("GroupAdjacentByKey(%v) stale group: got %v want none", src, got)
+			}
+		}
+	})
+	t.Run("empty", func(t *testing.T) {
+		t.Parallel()
+		for k := range GroupAdjacentByKey(FromEntries(slices.Values([]strIntPair(nil)))) {
+			t.Errorf("GroupAdjacentByKey(nil): got key %v want none", k)
+		}
+	})
+}
+
+func TestReduceByKey(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		src  []strIntPair
+		want []strIntPair
+	}{
+		{
+			[]strIntPair{{"a", 1}, {"a", 2}, {"b", 3}, {"a", 4}, {"c", 5}, {"c", 6}},
+			[]strIntPair{{"a", 3}, {"b", 3}, {"a", 4}, {"c", 11}},
+		},
+		{
+			[]strIntPair{{"a", 1}},
+			[]strIntPair{{"a", 1}},
+		},
+		{},
+	}
+	sum := func(a, b int) int { return a + b }
+
+	for _, tt := range tests {
+		got := collectPairs(ReduceByKey(FromEntries(slices.Values(tt.src)), sum))
+		if diff := cmp.Diff(tt.want, got); diff != "" {
+			t.Errorf("ReduceByKey(%v, sum): got %v want %v diff:\n%v", tt.src, got, tt.want, diff)
+		}
+	}
+}
+
+func TestFoldRuns(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		src  []string
+		want []Pair[byte, string]
+	}{
+		{
+			[]string{"apple", "avocado", "banana", "blueberry", "apricot"},
+			[]Pair[byte, string]{{'a', "a:apple,avocado"}, {'b', "b:banana,blueberry"}, {'a', "a:apricot"}},
+		},
+		{},
+	}
+	initial := func(k byte) string { return string(k) + ":" }
+	join := func(accum string, s string) string {
+		if !strings.HasSuffix(accum, ":") {
+			accum += ","
+		}
+		return accum + s
+	}
+
+	for _, tt := range tests {
+		got := collectPairs(FoldRuns(slices.Values(tt.src), func(s string) byte { return s[0] }, initial, join))
+		if diff := cmp.Diff(tt.want, got); diff != "" {
+			t.Errorf("FoldRuns(%v, firstLetter): got %v want %v diff:\n%v", tt.src, got, tt.want, diff)
+		}
+	}
+}
+
 func TestConcat(t *testing.T) {
 	t.Parallel()
 	tests := []struct {
@@ -541,3 +1110,38 @@ func TestConcat(t *testing.T) {
 		}
 	}
 }
+
+func TestInterleave(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		src          [][]int
+		want         []int
+		wantShortest []int
+	}{
+		{
+			[][]int{{1, 4, 7}, {2, 5}, {3, 6, 8, 9}},
+			[]int{1, 2, 3, 4, 5, 6, 7, 8, 9},
+			[]int{1, 2, 3, 4, 5, 6, 7},
+		},
+		{
+			[][]int{{1, 2}, nil},
+			[]int{1, 2},
+			[]int{1},
+		},
+		{},
+	}
+	for _, tt := range tests {
+		var in []iter.Seq[int]
+		for _, i := range tt.src {
+			in = append(in, slices.Values(i))
+		}
+		got := slices.Collect(Interleave(in...))
+		if diff := cmp.Diff(tt.want, got); diff != "" {
+			t.Errorf("Interleave(%v): got %v want %v diff:\n%v", tt.src, got, tt.want, diff)
+		}
+		got = slices.Collect(InterleaveShortest(in...))
+		if diff := cmp.Diff(tt.wantShortest, got); diff != "" {
+			t.Errorf("InterleaveShortest(%v): got %v want %v diff:\n%v", tt.src, got, tt.wantShortest, diff)
+		}
+	}
+}
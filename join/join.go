@@ -0,0 +1,134 @@
+// Package join provides operators to combine keyed iterators, in the spirit of
+// relational joins.
+package join
+
+import (
+	"cmp"
+	"iter"
+)
+
+// Match is a couple of values that share the same key, one from each side of a join.
+type Match[A, B any] struct {
+	Left  A
+	Right B
+	// Matched reports whether a Right value was found for Left.
+	// It is always true for inner joins.
+	Matched bool
+}
+
+// MergeJoin emits a [Match] for every couple of values from left and right that
+// share the same key, as an inner join would.
+//
+// Both sources must be sorted by key in ascending order. Since keys are matched with
+// a single pass on both sources, only the right values that share the current key are
+// kept in memory.
+func MergeJoin[K cmp.Ordered, A, B any](left iter.Seq2[K, A], right iter.Seq2[K, B]) iter.Seq2[K, Match[A, B]] {
+	return func(yield func(K, Match[A, B]) bool) {
+		nextL, stopL := iter.Pull2(left)
+		defer stopL()
+		nextR, stopR := iter.Pull2(right)
+		defer stopR()
+
+		var (
+			run    []B
+			runK   K
+			hasRun bool
+		)
+		lk, a, lok := nextL()
+		rk, b, rok := nextR()
+		for lok {
+			if hasRun && lk == runK {
+				for _, b := range run {
+					if !yield(lk, Match[A, B]{a, b, true}) {
+						return
+					}
+				}
+				lk, a, lok = nextL()
+				continue
+			}
+			for rok && rk < lk {
+				rk, b, rok = nextR()
+			}
+			if !rok || rk > lk {
+				lk, a, lok = nextL()
+				continue
+			}
+			run, runK, hasRun = run[:0], rk, true
+			for rok && rk == runK {
+				run = append(run, b)
+				rk, b, rok = nextR()
+			}
+		}
+	}
+}
+
+// LeftJoin emits a [Match] for every couple of values from left and right that
+// share the same key. Values from left that don't match any value from right are
+// emitted anyway, with the zero value for Right and Matched set to false.
+//
+// Right is consumed and kept in memory before the first value is emitted, while
+// left is streamed.
+func LeftJoin[K comparable, A, B any](left iter.Seq2[K, A], right iter.Seq2[K, B]) iter.Seq2[K, Match[A, B]] {
+	return func(yield func(K, Match[A, B]) bool) {
+		rights := map[K][]B{}
+		for k, b := range right {
+			rights[k] = append(rights[k], b)
+		}
+		for k, a := range left {
+			bs, ok := rights[k]
+			if !ok {
+				if !yield(k, Match[A, B]{Left: a}) {
+					return
+				}
+				continue
+			}
+			for _, b := range bs {
+				if !yield(k, Match[A, B]{a, b, true}) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// Group holds all the values that share the same key, from both sides of a join.
+type Group[A, B any] struct {
+	As []A
+	Bs []B
+}
+
+// CoGroup consumes both sources and emits a [Group] for every key they contain,
+// with all the values for that key from left and right.
+// Keys missing from one of the sources have an empty group for that side, which
+// allows to implement full and outer joins.
+//
+// Keys are emitted in order of first appearance, first in left and then in right.
+// All values are kept in memory.
+func CoGroup[K comparable, A, B any](left iter.Seq2[K, A], right iter.Seq2[K, B]) iter.Seq2[K, Group[A, B]] {
+	return func(yield func(K, Group[A, B]) bool) {
+		var keys []K
+		groups := map[K]*Group[A, B]{}
+		group := func(k K) *Group[A, B] {
+			g, ok := groups[k]
+			if !ok {
+				g = &Group[A, B]{}
+				groups[k] = g
+				keys = append(keys, k)
+			}
+			return g
+		}
+		for k, a := range left {
+			g := group(k)
+			g.As = append(g.As, a)
+		}
+		for k, b := range right {
+			g := group(k)
+			g.Bs = append(g.Bs, b)
+		}
+		for _, k := range keys {
+			if !yield(k, *groups[k]) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package join_test
+
+import (
+	"iter"
+	"testing"
+
+	"github.com/empijei/itertools/join"
+	"github.com/google/go-cmp/cmp"
+)
+
+type kv[K, V any] struct {
+	K K
+	V V
+}
+
+func seq2[K, V any](src ...kv[K, V]) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		for _, p := range src {
+			if !yield(p.K, p.V) {
+				return
+			}
+		}
+	}
+}
+
+func collect[K, V any](src iter.Seq2[K, V]) []kv[K, V] {
+	var got []kv[K, V]
+	for k, v := range src {
+		got = append(got, kv[K, V]{k, v})
+	}
+	return got
+}
+
+func TestMergeJoin(t *testing.T) {
+	left := seq2(kv[int, string]{1, "a"}, kv[int, string]{2, "b"}, kv[int, string]{2, "c"}, kv[int, string]{4, "d"}, kv[int, string]{6, "e"})
+	right := seq2(kv[int, bool]{0, true}, kv[int, bool]{2, true}, kv[int, bool]{2, false}, kv[int, bool]{5, true}, kv[int, bool]{6, false})
+
+	got := collect(join.MergeJoin(left, right))
+	type m = join.Match[string, bool]
+	want := []kv[int, m]{
+		{2, m{"b", true, true}},
+		{2, m{"b", false, true}},
+		{2, m{"c", true, true}},
+		{2, m{"c", false, true}},
+		{6, m{"e", false, true}},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("MergeJoin: got %v want %v diff:\n%v", got, want, diff)
+	}
+}
+
+func TestMergeJoinEmpty(t *testing.T) {
+	left := seq2(kv[int, string]{1, "a"})
+	right := seq2[int, bool]()
+	if got := collect(join.MergeJoin(left, right)); len(got) != 0 {
+		t.Errorf("MergeJoin(1, nil): got %v want none", got)
+	}
+	if got := collect(join.MergeJoin(right, left)); len(got) != 0 {
+		t.Errorf("MergeJoin(nil, 1): got %v want none", got)
+	}
+}
+
+func TestLeftJoin(t *testing.T) {
+	left := seq2(kv[string, int]{"a", 1}, kv[string, int]{"b", 2}, kv[string, int]{"c", 3})
+	right := seq2(kv[string, string]{"c", "x"}, kv[string, string]{"a", "y"}, kv[string, string]{"c", "z"})
+
+	got := collect(join.LeftJoin(left, right))
+	type m = join.Match[int, string]
+	want := []kv[string, m]{
+		{"a", m{Left: 1, Right: "y", Matched: true}},
+		{"b", m{Left: 2}},
+		{"c", m{Left: 3, Right: "x", Matched: true}},
+		{"c", m{Left: 3, Right: "z", Matched: true}},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("LeftJoin: got %v want %v diff:\n%v", got, want, diff)
+	}
+}
+
+func TestCoGroup(t *testing.T) {
+	left := seq2(kv[string, int]{"a", 1}, kv[string, int]{"b", 2}, kv[string, int]{"a", 3})
+	right := seq2(kv[string, bool]{"c", true}, kv[string, bool]{"a", false})
+
+	got := collect(join.CoGroup(left, right))
+	type g = join.Group[int, bool]
+	want := []kv[string, g]{
+		{"a", g{As: []int{1, 3}, Bs: []bool{false}}},
+		{"b", g{As: []int{2}}},
+		{"c", g{Bs: []bool{true}}},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("CoGroup: got %v want %v diff:\n%v", got, want, diff)
+	}
+}
@@ -0,0 +1,112 @@
+package to
+
+import (
+	"encoding"
+	"encoding/csv"
+	"fmt"
+	"iter"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CSVStructs writes a header row followed by one record per value emitted by src.
+//
+// T must be a struct type. Every exported field becomes a column, named after the
+// field's `csv:"name"` tag or, if the tag is missing, after the field itself.
+// Fields tagged with `csv:"-"` are skipped.
+//
+// Strings, booleans and numbers are formatted with the strconv package, values
+// implementing encoding.TextMarshaler are formatted with it and all other values are
+// formatted with fmt.Sprint.
+// time.Time fields are formatted as RFC 3339 unless the tag specifies a layout for
+// time.Format as its last option, e.g. `csv:"created,layout=2006-01-02"`.
+//
+// CSVStructs flushes w before returning.
+func CSVStructs[T any](w *csv.Writer, src iter.Seq[T]) error {
+	typ := reflect.TypeFor[T]()
+	if typ.Kind() != reflect.Struct {
+		return fmt.Errorf("%v is not a struct type", typ)
+	}
+	var (
+		header  []string
+		fields  []int
+		layouts []string
+	)
+	for i := range typ.NumField() {
+		f := typ.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		name, layout := csvTag(f)
+		if name == "-" {
+			continue
+		}
+		header = append(header, name)
+		fields = append(fields, i)
+		layouts = append(layouts, layout)
+	}
+	if err := w.Write(header); err != nil {
+		return err
+	}
+	record := make([]string, len(fields))
+	for t := range src {
+		v := reflect.ValueOf(t)
+		for i, f := range fields {
+			s, err := formatCSVField(v.Field(f), layouts[i])
+			if err != nil {
+				return fmt.Errorf("field %q: %w", header[i], err)
+			}
+			record[i] = s
+		}
+		if err := w.Write(record); err != nil {
+			return err
+		}
+	}
+	w.Flush()
+	return w.Error()
+}
+
+// csvTag returns the column name and the time layout for f, as specified by its
+// csv tag. The layout is the last option, so that it may contain commas.
+func csvTag(f reflect.StructField) (name, layout string) {
+	tag, ok := f.Tag.Lookup("csv")
+	if !ok {
+		return f.Name, ""
+	}
+	tag, layout, _ = strings.Cut(tag, ",layout=")
+	name, _, _ = strings.Cut(tag, ",")
+	if name == "" {
+		name = f.Name
+	}
+	return name, layout
+}
+
+func formatCSVField(v reflect.Value, layout string) (string, error) {
+	if layout != "" {
+		t, ok := v.Interface().(time.Time)
+		if !ok {
+			return "", fmt.Errorf("layout specified for non-time type %v", v.Type())
+		}
+		return t.Format(layout), nil
+	}
+	if m, ok := v.Interface().(encoding.TextMarshaler); ok {
+		b, err := m.MarshalText()
+		return string(b), err
+	}
+	switch v.Kind() {
+	case reflect.String:
+		return v.String(), nil
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool()), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.FormatUint(v.Uint(), 10), nil
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'g', -1, v.Type().Bits()), nil
+	default:
+		return fmt.Sprint(v.Interface()), nil
+	}
+}
@@ -0,0 +1,75 @@
+package to_test
+
+import (
+	"encoding/csv"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/empijei/itertools/to"
+	"github.com/google/go-cmp/cmp"
+)
+
+type csvRecord struct {
+	Name    string    `csv:"name"`
+	Age     int       `csv:"age"`
+	Score   float64   `csv:"score"`
+	Active  bool      `csv:"active"`
+	Created time.Time `csv:"created"`
+	Notes   string
+	Ignored string `csv:"-"`
+	private string
+}
+
+func TestCSVStructs(t *testing.T) {
+	created := time.Date(2024, 11, 8, 19, 4, 13, 0, time.UTC)
+	src := []csvRecord{
+		{Name: "Alice", Age: 30, Score: 9.5, Active: true, Created: created, Notes: "a, b", Ignored: "x", private: "y"},
+		{Name: "Bob", Age: 25, Score: 7, Created: created},
+	}
+	var sb strings.Builder
+	if err := to.CSVStructs(csv.NewWriter(&sb), slices.Values(src)); err != nil {
+		t.Fatalf("CSVStructs: got err %v want nil", err)
+	}
+	got := sb.String()
+	want := `name,age,score,active,created,Notes
+Alice,30,9.5,true,2024-11-08T19:04:13Z,"a, b"
+Bob,25,7,false,2024-11-08T19:04:13Z,
+`
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("CSVStructs: got %q want %q diff:\n%v", got, want, diff)
+	}
+}
+
+func TestCSVStructsNotStruct(t *testing.T) {
+	var sb strings.Builder
+	if err := to.CSVStructs(csv.NewWriter(&sb), slices.Values([]int{1, 2})); err == nil {
+		t.Errorf("CSVStructs(ints): got nil err, want error")
+	}
+}
+
+func TestCSVStructsLayout(t *testing.T) {
+	type event struct {
+		Day  time.Time `csv:"day,layout=Jan 2, 2006"`
+		Name string    `csv:",omitted"`
+	}
+	src := []event{{Day: time.Date(2024, 11, 8, 0, 0, 0, 0, time.UTC), Name: "launch"}}
+	var sb strings.Builder
+	if err := to.CSVStructs(csv.NewWriter(&sb), slices.Values(src)); err != nil {
+		t.Fatalf("CSVStructs: got err %v want nil", err)
+	}
+	want := `day,Name
+"Nov 8, 2024",launch
+`
+	if diff := cmp.Diff(want, sb.String()); diff != "" {
+		t.Errorf("CSVStructs(%v): got %q want %q diff:\n%v", src, sb.String(), want, diff)
+	}
+
+	type bad struct {
+		N int `csv:"n,layout=2006"`
+	}
+	if err := to.CSVStructs(csv.NewWriter(&sb), slices.Values([]bad{{1}})); err == nil {
+		t.Errorf("CSVStructs(layout on int): got nil err, want error")
+	}
+}
@@ -0,0 +1,58 @@
+package to
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"iter"
+)
+
+// SQLBatch executes query once for every row emitted by src, using the row as the
+// query arguments.
+//
+// Rows are accumulated in batches of batchSize, and every batch is executed in its
+// own transaction with a single prepared statement. Batches that were committed
+// before an error occurred are not rolled back.
+//
+// It stops consuming src and returns an error as soon as a statement fails or ctx
+// is done.
+func SQLBatch(ctx context.Context, db *sql.DB, query string, src iter.Seq[[]any], batchSize int) error {
+	if batchSize <= 0 {
+		return fmt.Errorf("batch size %d is not positive", batchSize)
+	}
+	batch := make([][]any, 0, batchSize)
+	for row := range src {
+		batch = append(batch, row)
+		if len(batch) < batchSize {
+			continue
+		}
+		if err := execBatch(ctx, db, query, batch); err != nil {
+			return err
+		}
+		batch = batch[:0]
+	}
+	if len(batch) == 0 {
+		return nil
+	}
+	return execBatch(ctx, db, query, batch)
+}
+
+func execBatch(ctx context.Context, db *sql.DB, query string, batch [][]any) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	// Rollback is a no-op after a successful Commit.
+	defer tx.Rollback()
+	stmt, err := tx.PrepareContext(ctx, query)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+	for _, row := range batch {
+		if _, err := stmt.ExecContext(ctx, row...); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
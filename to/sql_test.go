@@ -0,0 +1,130 @@
+package to_test
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/empijei/itertools/to"
+	"github.com/google/go-cmp/cmp"
+)
+
+// recordingDriver is a database/sql driver that records the operations performed
+// on its connections as strings.
+type recordingDriver struct {
+	mu  sync.Mutex
+	log []string
+}
+
+func (d *recordingDriver) record(format string, args ...any) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.log = append(d.log, fmt.Sprintf(format, args...))
+}
+
+func (d *recordingDriver) Open(string) (driver.Conn, error) { return recordingConn{d}, nil }
+
+type recordingConn struct{ d *recordingDriver }
+
+func (c recordingConn) Prepare(query string) (driver.Stmt, error) {
+	return recordingStmt{c.d}, nil
+}
+func (c recordingConn) Close() error { return nil }
+func (c recordingConn) Begin() (driver.Tx, error) {
+	c.d.record("begin")
+	return recordingTx{c.d}, nil
+}
+
+type recordingTx struct{ d *recordingDriver }
+
+func (tx recordingTx) Commit() error   { tx.d.record("commit"); return nil }
+func (tx recordingTx) Rollback() error { tx.d.record("rollback"); return nil }
+
+type recordingStmt struct{ d *recordingDriver }
+
+func (s recordingStmt) Close() error  { return nil }
+func (s recordingStmt) NumInput() int { return -1 }
+func (s recordingStmt) Exec(args []driver.Value) (driver.Result, error) {
+	if slices.Contains(args, driver.Value("fail")) {
+		return nil, errors.New("exec failed")
+	}
+	s.d.record("exec %v", args)
+	return driver.RowsAffected(1), nil
+}
+func (s recordingStmt) Query([]driver.Value) (driver.Rows, error) {
+	return nil, errors.New("not implemented")
+}
+
+var sqlDriverCount int
+
+func openRecordingDB(t *testing.T) (*sql.DB, *recordingDriver) {
+	t.Helper()
+	d := &recordingDriver{}
+	sqlDriverCount++
+	name := fmt.Sprintf("recording%d", sqlDriverCount)
+	sql.Register(name, d)
+	db, err := sql.Open(name, "")
+	if err != nil {
+		t.Fatalf("sql.Open: %v", err)
+	}
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+	return db, d
+}
+
+func TestSQLBatch(t *testing.T) {
+	tests := []struct {
+		name      string
+		rows      [][]any
+		batchSize int
+		want      []string
+		wantErr   bool
+	}{
+		{
+			name:      "empty",
+			batchSize: 2,
+		},
+		{
+			name:      "partial last batch",
+			rows:      [][]any{{"a", 1}, {"b", 2}, {"c", 3}},
+			batchSize: 2,
+			want:      []string{"begin", "exec [a 1]", "exec [b 2]", "commit", "begin", "exec [c 3]", "commit"},
+		},
+		{
+			name:      "exact batches",
+			rows:      [][]any{{"a", 1}, {"b", 2}},
+			batchSize: 1,
+			want:      []string{"begin", "exec [a 1]", "commit", "begin", "exec [b 2]", "commit"},
+		},
+		{
+			name:      "failure rolls back batch",
+			rows:      [][]any{{"a", 1}, {"b", 2}, {"fail", 3}, {"d", 4}},
+			batchSize: 2,
+			want:      []string{"begin", "exec [a 1]", "exec [b 2]", "commit", "begin", "rollback"},
+			wantErr:   true,
+		},
+		{
+			name:      "invalid batch size",
+			rows:      [][]any{{"a", 1}},
+			batchSize: 0,
+			wantErr:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, d := openRecordingDB(t)
+			err := to.SQLBatch(context.Background(), db, "INSERT INTO t VALUES (?, ?)", slices.Values(tt.rows), tt.batchSize)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("SQLBatch(%v, %v): got err %v want err %v", tt.rows, tt.batchSize, err, tt.wantErr)
+			}
+			if diff := cmp.Diff(tt.want, d.log); diff != "" {
+				t.Errorf("SQLBatch(%v, %v): got %v want %v diff:\n%v", tt.rows, tt.batchSize, d.log, tt.want, diff)
+			}
+		})
+	}
+}
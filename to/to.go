@@ -2,9 +2,24 @@
 package to
 
 import (
+	"bufio"
+	"cmp"
+	"container/heap"
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
 	"iter"
 	"maps"
+	"math"
+	"math/bits"
+	"math/rand/v2"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/empijei/itertools"
 	"golang.org/x/exp/constraints"
@@ -14,6 +29,13 @@ type empty = struct{}
 
 func zero[T any]() (zero T) { return }
 
+var (
+	// ErrNoValues is returned when a source was expected to emit values but it didn't.
+	ErrNoValues = errors.New("no values")
+	// ErrTooManyValues is returned when a source emitted more values than expected.
+	ErrTooManyValues = errors.New("too many values")
+)
+
 // First returns the first value for which predicate returns true and stops consuming src.
 func First[T any](src iter.Seq[T], predicate func(T) bool) (t T, found bool) {
 	for t := range src {
@@ -24,6 +46,95 @@ func First[T any](src iter.Seq[T], predicate func(T) bool) (t T, found bool) {
 	return zero[T](), false
 }
 
+// Last consumes the entire source and returns the last value it emitted, and reports
+// whether at least one value was consumed.
+func Last[T any](src iter.Seq[T]) (t T, found bool) {
+	return LastFunc(src, func(T) bool { return true })
+}
+
+// LastFunc consumes the entire source and returns the last value for which predicate returns true.
+func LastFunc[T any](src iter.Seq[T], predicate func(T) bool) (t T, found bool) {
+	var last T
+	for t := range src {
+		if predicate(t) {
+			last, found = t, true
+		}
+	}
+	return last, found
+}
+
+// Nth returns the n-th value emitted by src, counting from 0, and reports whether
+// src emitted at least n+1 values.
+// It stops consuming src after the n-th value.
+func Nth[T any](src iter.Seq[T], n int) (t T, found bool) {
+	if n < 0 {
+		return zero[T](), false
+	}
+	i := 0
+	for t := range src {
+		if i == n {
+			return t, true
+		}
+		i++
+	}
+	return zero[T](), false
+}
+
+// Single returns the only value emitted by src.
+// It returns [ErrNoValues] if src is empty and [ErrTooManyValues] if src emits a
+// second value, in which case it stops consuming src.
+func Single[T any](src iter.Seq[T]) (T, error) {
+	var single T
+	var found bool
+	for t := range src {
+		if found {
+			return zero[T](), ErrTooManyValues
+		}
+		single, found = t, true
+	}
+	if !found {
+		return zero[T](), ErrNoValues
+	}
+	return single, nil
+}
+
+// IndexOf returns the position of the first value for which predicate returns true,
+// counting from 0, or -1 if there is none.
+// It stops consuming src at the first match.
+func IndexOf[T any](src iter.Seq[T], predicate func(T) bool) int {
+	i := 0
+	for t := range src {
+		if predicate(t) {
+			return i
+		}
+		i++
+	}
+	return -1
+}
+
+// Mismatch consumes a and b in lockstep and reports the position of the first values
+// that differ, together with the values themselves.
+// If one of the sources is shorter than the other, the mismatch is at the position
+// where it ended, and its value is reported as the zero value.
+// If a and b emit the same values ok is false.
+//
+// It stops consuming both sources at the first mismatch.
+func Mismatch[T comparable](a, b iter.Seq[T]) (index int, va, vb T, ok bool) {
+	nextB, stop := iter.Pull(b)
+	defer stop()
+	for ta := range a {
+		tb, okB := nextB()
+		if !okB || ta != tb {
+			return index, ta, tb, true
+		}
+		index++
+	}
+	if tb, okB := nextB(); okB {
+		return index, zero[T](), tb, true
+	}
+	return index, zero[T](), zero[T](), false
+}
+
 // Contains reports whether there is at least one value in the source iterator for which
 // predicate returns true.
 // It stops consuming the source at the first match.
@@ -64,6 +175,21 @@ func Max[T constraints.Ordered](src iter.Seq[T]) (_ T, ok bool) {
 	return m, init
 }
 
+// MinMax returns both the minimum and maximum elements emitted by the source in a
+// single pass, and reports whether at least one value was consumed.
+func MinMax[T constraints.Ordered](src iter.Seq[T]) (minimum, maximum T, ok bool) {
+	for t := range src {
+		if !ok {
+			ok = true
+			minimum, maximum = t, t
+			continue
+		}
+		minimum = min(minimum, t)
+		maximum = max(maximum, t)
+	}
+	return minimum, maximum, ok
+}
+
 // Len consumes the entire source and reports how many values it consumed.
 func Len[T any](src iter.Seq[T]) int {
 	var c int
@@ -73,6 +199,44 @@ func Len[T any](src iter.Seq[T]) int {
 	return c
 }
 
+// Number is a constraint that permits any numeric type.
+type Number interface {
+	constraints.Integer | constraints.Float | constraints.Complex
+}
+
+// Sum consumes the entire source and returns the sum of all the values it consumed.
+// It returns 0 for empty sources.
+func Sum[T Number](src iter.Seq[T]) T {
+	var s T
+	for t := range src {
+		s += t
+	}
+	return s
+}
+
+// Product consumes the entire source and returns the product of all the values it consumed.
+// It returns 1 for empty sources.
+func Product[T Number](src iter.Seq[T]) T {
+	p := T(1)
+	for t := range src {
+		p *= t
+	}
+	return p
+}
+
+// Mean returns the arithmetic mean of the values emitted by the source and reports
+// whether at least one value was consumed.
+// The mean is updated incrementally, so large sums don't overflow.
+func Mean[T constraints.Integer | constraints.Float](src iter.Seq[T]) (_ float64, ok bool) {
+	var m float64
+	var n int
+	for t := range src {
+		n++
+		m += (float64(t) - m) / float64(n)
+	}
+	return m, n > 0
+}
+
 // Reduce scans the source and applies predicate on all elements it consumes until
 // the predicate returns false or the source is exhausted.
 //
@@ -94,6 +258,30 @@ func Reduce[T any](src iter.Seq[T],
 	return accum
 }
 
+// Reduce2 is like [Reduce] for iter.Seq2, with an accumulator that can have a
+// different type than the consumed values.
+func Reduce2[K, V, A any](src iter.Seq2[K, V],
+	startAccum A,
+	predicate func(accum A, k K, v V) (newAccum A, ok bool)) (lastAccum A) {
+	accum := startAccum
+	for k, v := range src {
+		var ok bool
+		accum, ok = predicate(accum, k, v)
+		if !ok {
+			return accum
+		}
+	}
+	return accum
+}
+
+// Fold2 consumes the entire source and applies f to all couples, passing subsequent
+// accumulator values to each call and returning the last one.
+func Fold2[K, V, A any](src iter.Seq2[K, V], startAccum A, f func(accum A, k K, v V) A) A {
+	return Reduce2(src, startAccum, func(accum A, k K, v V) (A, bool) {
+		return f(accum, k, v), true
+	})
+}
+
 // Chan spawns a goroutine that consumes values emitted by the source and sends them
 // on the returned channel.
 // The channel is created with the provided buf size.
@@ -104,28 +292,669 @@ func Reduce[T any](src iter.Seq[T],
 // once a yield call is performed by the source.
 // Users of Chan should make sure that the source iterator stops when the related
 // context is done.
-func Chan[T any](ctx context.Context, src iter.Seq[T], buf int) <-chan T {
+func Chan[T any](ctx context.Context, src iter.Seq[T], buf int, opts ...ChanOption) <-chan T {
+	var o chanOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.overflow == SampleLatest {
+		buf = 1
+	}
 	c := make(chan T, buf)
 	go func() {
 		defer close(c)
+		var st ChanStats
+		var err error
+		defer func() {
+			if o.stats != nil {
+				o.stats(st)
+			}
+			if o.onDone != nil {
+				o.onDone(err)
+			}
+		}()
+		// Timestamps are only taken when stats are collected: with a zero clock all
+		// the durations below are zero.
+		now := func() time.Time { return time.Time{} }
+		if o.stats != nil {
+			now = time.Now
+		}
+		waitStart := now()
 		for t := range src {
+			st.SourceWait += now().Sub(waitStart)
 			// Make sure we stop as soon as possible.
 			select {
 			case <-ctx.Done():
 			default:
 			}
+			sendStart := now()
+			if o.overflow != Block {
+				if err = ctx.Err(); err != nil {
+					return
+				}
+				sendOrDrop(c, t, o.overflow, &st)
+				waitStart = now()
+				continue
+			}
 			// Actually try to send the value
 			select {
 			case <-ctx.Done():
+				st.SendBlocked += now().Sub(sendStart)
+				err = ctx.Err()
 				return
 			case c <- t:
+				st.Sent++
 			}
+			waitStart = now()
+			st.SendBlocked += waitStart.Sub(sendStart)
 		}
 	}()
 	return c
 }
 
+// sendOrDrop sends t on c without blocking, applying p if c is full.
+func sendOrDrop[T any](c chan T, t T, p OverflowPolicy, st *ChanStats) {
+	select {
+	case c <- t:
+		st.Sent++
+		return
+	default:
+	}
+	if p == DropNewest {
+		st.Dropped++
+		return
+	}
+	// Make room by discarding the oldest value, unless the consumer just did.
+	select {
+	case <-c:
+		st.Dropped++
+	default:
+	}
+	select {
+	case c <- t:
+		st.Sent++
+	default:
+		// Unbuffered channels have no room to make.
+		st.Dropped++
+	}
+}
+
+// ChanStats reports how the goroutine spawned by [Chan] spent its time, which
+// can be used to tell whether the producer or the consumer is the bottleneck.
+type ChanStats struct {
+	// Sent is the number of values sent on the channel.
+	Sent int
+	// SendBlocked is the total time spent waiting for the consumer to receive values.
+	SendBlocked time.Duration
+	// SourceWait is the total time spent waiting for the source to emit values.
+	SourceWait time.Duration
+	// Dropped is the number of values discarded because of the [OverflowPolicy],
+	// including the ones that were removed from the channel after being sent.
+	Dropped int
+}
+
+// OverflowPolicy controls what [Chan] does with values when the consumer is slower
+// than the source and the channel is full.
+type OverflowPolicy int
+
+const (
+	// Block waits for the consumer to receive values, stalling the source.
+	// This is the default.
+	Block OverflowPolicy = iota
+	// DropNewest discards values that don't fit in the channel.
+	DropNewest
+	// DropOldest discards the oldest value in the channel to make room for the new one.
+	// For unbuffered channels it behaves like DropNewest.
+	DropOldest
+	// SampleLatest makes the channel hold only the latest value emitted by the source,
+	// regardless of the requested buffer size, so that consumers always receive the
+	// most recent value.
+	SampleLatest
+)
+
+// ChanOption configures the behavior of [Chan].
+type ChanOption func(*chanOptions)
+
+type chanOptions struct {
+	stats    func(ChanStats)
+	onDone   func(error)
+	overflow OverflowPolicy
+}
+
+// WithStats makes [Chan] call report once it stops consuming the source, right
+// before the returned channel is closed.
+func WithStats(report func(ChanStats)) ChanOption {
+	return func(o *chanOptions) {
+		o.stats = report
+	}
+}
+
+// WithOnDone makes [Chan] call done once it stops consuming the source, right
+// before the returned channel is closed and after the [WithStats] report, if any.
+// The error is nil if the source was exhausted and the context error if Chan stopped
+// because the context was done.
+func WithOnDone(done func(err error)) ChanOption {
+	return func(o *chanOptions) {
+		o.onDone = done
+	}
+}
+
+// WithOverflow sets the policy [Chan] applies when the channel is full.
+// With policies other than Block the source is never stalled by a slow consumer.
+func WithOverflow(p OverflowPolicy) ChanOption {
+	return func(o *chanOptions) {
+		o.overflow = p
+	}
+}
+
+// Writer writes all the byte slices emitted by src to w, and returns the number of
+// bytes written. It stops consuming src at the first write error.
+func Writer(w io.Writer, src iter.Seq[[]byte]) (int64, error) {
+	var written int64
+	for b := range src {
+		n, err := w.Write(b)
+		written += int64(n)
+		if err != nil {
+			return written, err
+		}
+	}
+	return written, nil
+}
+
+// WriterString is like [Writer] for strings. If delim is not empty it is written
+// after every string, e.g. "\n" writes one string per line.
+func WriterString(w io.Writer, src iter.Seq[string], delim string) (int64, error) {
+	var written int64
+	for s := range src {
+		n, err := io.WriteString(w, s)
+		written += int64(n)
+		if err != nil {
+			return written, err
+		}
+		if delim == "" {
+			continue
+		}
+		n, err = io.WriteString(w, delim)
+		written += int64(n)
+		if err != nil {
+			return written, err
+		}
+	}
+	return written, nil
+}
+
+// JSONLines writes every value emitted by src to w as a JSON document followed by
+// a newline, in the NDJSON format.
+// It stops consuming src at the first encoding or write error.
+func JSONLines[T any](w io.Writer, src iter.Seq[T]) error {
+	enc := json.NewEncoder(w)
+	for t := range src {
+		if err := enc.Encode(t); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// File writes one string emitted by src per line to the file at path, creating it
+// if needed and truncating it unless [WithAppend] is passed.
+// Writes are buffered. The first error encountered is returned, including the one
+// returned by closing the file.
+func File(path string, src iter.Seq[string], opts ...FileOption) (err error) {
+	o := fileOptions{perm: 0o666, flag: os.O_TRUNC}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|o.flag, o.perm)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+	}()
+	bw := bufio.NewWriter(f)
+	if _, err := WriterString(bw, src, "\n"); err != nil {
+		return err
+	}
+	return bw.Flush()
+}
+
+// FileOption configures [File].
+type FileOption func(*fileOptions)
+
+type fileOptions struct {
+	perm os.FileMode
+	flag int
+}
+
+// WithFileMode sets the permissions [File] uses when creating the file.
+// The default is 0666, before umask.
+func WithFileMode(perm os.FileMode) FileOption {
+	return func(o *fileOptions) {
+		o.perm = perm
+	}
+}
+
+// WithAppend makes [File] append to the file instead of truncating it.
+func WithAppend() FileOption {
+	return func(o *fileOptions) {
+		o.flag = os.O_APPEND
+	}
+}
+
+// CountDistinct consumes the source and returns the number of distinct values it
+// emitted.
+//
+// It keeps all distinct values in memory, see [CountDistinctApprox] for large sources.
+func CountDistinct[T comparable](src iter.Seq[T]) int {
+	return len(Set(src))
+}
+
+// hllPrecision is the number of hash bits CountDistinctApprox uses to pick a register.
+const hllPrecision = 14
+
+// CountDistinctApprox consumes the source and estimates the number of distinct values
+// it emitted using the HyperLogLog algorithm.
+//
+// It uses 16KiB of memory regardless of the number of values, with a typical relative
+// error of about 1%. The hash function must distribute values uniformly over all 64
+// bits, e.g. by using hash/maphash.
+func CountDistinctApprox[T any](src iter.Seq[T], hash func(T) uint64) int {
+	const m = 1 << hllPrecision
+	var registers [m]uint8
+	for t := range src {
+		h := hash(t)
+		idx := h >> (64 - hllPrecision)
+		// Set a guard bit so that the rank never exceeds the remaining bits.
+		rank := uint8(bits.LeadingZeros64(h<<hllPrecision|1<<(hllPrecision-1))) + 1
+		registers[idx] = max(registers[idx], rank)
+	}
+	var sum float64
+	var empties int
+	for _, r := range registers {
+		sum += math.Ldexp(1, -int(r))
+		if r == 0 {
+			empties++
+		}
+	}
+	alpha := 0.7213 / (1 + 1.079/m)
+	estimate := alpha * m * m / sum
+	if estimate <= 2.5*m && empties > 0 {
+		// Linear counting is more accurate for small cardinalities.
+		estimate = m * math.Log(float64(m)/float64(empties))
+	}
+	return int(math.Round(estimate))
+}
+
+// String concatenates all the runes emitted by src into a string.
+func String(src iter.Seq[rune]) string {
+	var sb strings.Builder
+	for r := range src {
+		sb.WriteRune(r)
+	}
+	return sb.String()
+}
+
+// StringFromBytes concatenates all the bytes emitted by src into a string.
+func StringFromBytes(src iter.Seq[byte]) string {
+	var sb strings.Builder
+	for b := range src {
+		sb.WriteByte(b)
+	}
+	return sb.String()
+}
+
 // Set returns a map that has src values as keys.
 func Set[T comparable](src iter.Seq[T]) map[T]empty {
 	return maps.Collect(itertools.EmptyValues(src))
 }
+
+// SortedSlice consumes the source and returns its values sorted in ascending order.
+// It is equivalent to slices.Sorted.
+func SortedSlice[T constraints.Ordered](src iter.Seq[T]) []T {
+	return slices.Sorted(src)
+}
+
+// SortedSliceFunc is like [SortedSlice] but sorts values according to cmp.
+// It is equivalent to slices.SortedFunc.
+func SortedSliceFunc[T any](src iter.Seq[T], cmp func(a, b T) int) []T {
+	return slices.SortedFunc(src, cmp)
+}
+
+// IsSorted reports whether the values emitted by src are in ascending order.
+// It stops consuming the source at the first value that is out of order.
+func IsSorted[T constraints.Ordered](src iter.Seq[T]) bool {
+	return IsSortedFunc(src, cmp.Compare[T])
+}
+
+// IsSortedFunc is like [IsSorted] but compares values with cmp.
+func IsSortedFunc[T any](src iter.Seq[T], cmp func(a, b T) int) bool {
+	first := true
+	var prev T
+	for t := range src {
+		if !first && cmp(t, prev) < 0 {
+			return false
+		}
+		first = false
+		prev = t
+	}
+	return true
+}
+
+// Partition consumes the source and splits its values in two slices: the ones for
+// which predicate returns true and the ones for which it returns false.
+// Values keep their relative order.
+func Partition[T any](src iter.Seq[T], predicate func(T) bool) (yes, no []T) {
+	for t := range src {
+		if predicate(t) {
+			yes = append(yes, t)
+		} else {
+			no = append(no, t)
+		}
+	}
+	return yes, no
+}
+
+// Pages groups the values emitted by src in pages of size values, consuming the
+// source only as pages are requested. The last page may be shorter.
+// If size is not positive no page is emitted.
+//
+// Every page is newly allocated, so consumers can retain it.
+func Pages[T any](src iter.Seq[T], size int) iter.Seq[[]T] {
+	return func(yield func([]T) bool) {
+		if size <= 0 {
+			return
+		}
+		var page []T
+		for t := range src {
+			if page == nil {
+				page = make([]T, 0, size)
+			}
+			page = append(page, t)
+			if len(page) < size {
+				continue
+			}
+			if !yield(page) {
+				return
+			}
+			page = nil
+		}
+		if len(page) > 0 {
+			yield(page)
+		}
+	}
+}
+
+// Page returns the values of the zero-based page of the given size, skipping the
+// values of all previous pages and without consuming the source past the page end.
+// It returns nil if the page is past the end of the source or the arguments are
+// not valid.
+func Page[T any](src iter.Seq[T], page, size int) []T {
+	if page < 0 || size <= 0 {
+		return nil
+	}
+	start := page * size
+	var vs []T
+	i := 0
+	for t := range src {
+		if i >= start {
+			vs = append(vs, t)
+			if len(vs) == size {
+				break
+			}
+		}
+		i++
+	}
+	return vs
+}
+
+// Unzip consumes the source and returns its keys and values as two separate slices,
+// in the order they were emitted.
+func Unzip[K, V any](src iter.Seq2[K, V]) ([]K, []V) {
+	var ks []K
+	var vs []V
+	for k, v := range src {
+		ks = append(ks, k)
+		vs = append(vs, v)
+	}
+	return ks, vs
+}
+
+// Duplicates consumes the source and returns the values that were emitted more
+// than once, together with the number of times they were emitted.
+func Duplicates[T comparable](src iter.Seq[T]) map[T]int {
+	counts := map[T]int{}
+	for t := range src {
+		counts[t]++
+	}
+	maps.DeleteFunc(counts, func(_ T, c int) bool {
+		return c < 2
+	})
+	return counts
+}
+
+// MapGrouped consumes the source and returns a map from every key to all the values
+// emitted with it, in order.
+func MapGrouped[K comparable, V any](src iter.Seq2[K, V]) map[K][]V {
+	m := map[K][]V{}
+	for k, v := range src {
+		m[k] = append(m[k], v)
+	}
+	return m
+}
+
+// AggregateByKey consumes the source and folds the values of every key with f,
+// starting from seed. It returns a map from every key to its folded value.
+func AggregateByKey[K comparable, V, A any](src iter.Seq2[K, V], seed A, f func(A, V) A) map[K]A {
+	aggr := map[K]A{}
+	for k, v := range src {
+		accum, ok := aggr[k]
+		if !ok {
+			accum = seed
+		}
+		aggr[k] = f(accum, v)
+	}
+	return aggr
+}
+
+// Fill copies values emitted by src into dst until either dst is full or src is exhausted,
+// and returns the number of values copied.
+//
+// Fill never consumes values past the end of dst, so it cannot tell whether a source
+// that exactly fills dst has more values: more reports whether dst was filled, in
+// which case src may have more values. To page through a source, src should be an
+// iterator that resumes where the previous consumer stopped, such as one built on
+// iter.Pull, and paging ends once Fill returns more as false.
+func Fill[T any](src iter.Seq[T], dst []T) (n int, more bool) {
+	if len(dst) == 0 {
+		return 0, true
+	}
+	for t := range src {
+		dst[n] = t
+		n++
+		if n == len(dst) {
+			break
+		}
+	}
+	return n, n == len(dst)
+}
+
+// Shard routes every value emitted by src to one of n shards, as chosen by pick,
+// by calling sink with the shard index and the value.
+//
+// It stops consuming src and returns an error as soon as sink fails or pick returns
+// an index outside of [0, n).
+func Shard[T any](src iter.Seq[T], n int, pick func(T) int, sink func(shard int, v T) error) error {
+	for t := range src {
+		shard := pick(t)
+		if shard < 0 || shard >= n {
+			return fmt.Errorf("picked shard %d out of %d", shard, n)
+		}
+		if err := sink(shard, t); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Drain consumes and discards all values emitted by src, which is useful for sources
+// that are only run for their side effects.
+// It stops as soon as ctx is done and returns how many values it consumed, and the
+// context error, if any.
+func Drain[T any](ctx context.Context, src iter.Seq[T]) (int, error) {
+	var n int
+	for range src {
+		if err := ctx.Err(); err != nil {
+			return n, err
+		}
+		n++
+	}
+	return n, ctx.Err()
+}
+
+// ForEach calls f for every value emitted by src.
+// It stops at the first error returned by f, which it returns, or as soon as ctx
+// is done, in which case it returns the context error.
+func ForEach[T any](ctx context.Context, src iter.Seq[T], f func(T) error) error {
+	for t := range src {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := f(t); err != nil {
+			return err
+		}
+	}
+	return ctx.Err()
+}
+
+// Sink is a custom terminal for iterators, see [Into].
+type Sink[T any] interface {
+	// Accept consumes a value and reports whether the sink wants more.
+	Accept(T) bool
+	// Close is called once the sink stops accepting values or the source is exhausted.
+	Close() error
+}
+
+// Into feeds all values emitted by src to sink until sink stops accepting them,
+// then closes sink and returns the error returned by Close.
+func Into[T any](src iter.Seq[T], sink Sink[T]) error {
+	for t := range src {
+		if !sink.Accept(t) {
+			break
+		}
+	}
+	return sink.Close()
+}
+
+// RunAll runs all the pipelines concurrently and waits for them to return.
+//
+// The context passed to the pipelines is cancelled as soon as one of them fails,
+// and RunAll returns the first error encountered.
+func RunAll(ctx context.Context, pipelines ...func(context.Context) error) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	var (
+		wg    sync.WaitGroup
+		once  sync.Once
+		first error
+	)
+	for _, p := range pipelines {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := p(ctx); err != nil {
+				once.Do(func() {
+					first = err
+					cancel()
+				})
+			}
+		}()
+	}
+	wg.Wait()
+	return first
+}
+
+// Job returns a pipeline for [RunAll] that calls [ForEach] with src and sink.
+func Job[T any](src iter.Seq[T], sink func(T) error) func(context.Context) error {
+	return func(ctx context.Context) error {
+		return ForEach(ctx, src, sink)
+	}
+}
+
+// SampleWeighted returns up to k values emitted by src chosen at random, with
+// each value having a probability of being chosen proportional to its weight.
+// Values with a weight that is not positive are never chosen.
+//
+// It implements the A-Res weighted reservoir sampling algorithm, so it consumes
+// src once and only retains k values at a time.
+func SampleWeighted[T any](src iter.Seq[T], k int, weight func(T) float64, r *rand.Rand) []T {
+	type keyed struct {
+		key float64
+		t   T
+	}
+	h := &boundedHeap[keyed]{n: k, less: func(a, b keyed) bool {
+		return a.key < b.key
+	}}
+	for t := range src {
+		w := weight(t)
+		if w <= 0 {
+			continue
+		}
+		h.offer(keyed{key: math.Pow(r.Float64(), 1/w), t: t})
+	}
+	slices.SortFunc(h.items, func(a, b keyed) int {
+		return -cmp.Compare(a.key, b.key)
+	})
+	sample := make([]T, 0, len(h.items))
+	for _, kt := range h.items {
+		sample = append(sample, kt.t)
+	}
+	return sample
+}
+
+// TopN returns the n greatest values emitted by src according to cmp, sorted from
+// the greatest to the least.
+// It consumes src once and only retains n values at a time.
+func TopN[T any](src iter.Seq[T], n int, cmp func(a, b T) int) []T {
+	h := &boundedHeap[T]{n: n, less: func(a, b T) bool {
+		return cmp(a, b) < 0
+	}}
+	for t := range src {
+		h.offer(t)
+	}
+	slices.SortFunc(h.items, func(a, b T) int {
+		return cmp(b, a)
+	})
+	return h.items
+}
+
+// boundedHeap retains the n greatest values it is offered, according to less.
+// Its root is the least retained value.
+type boundedHeap[T any] struct {
+	n     int
+	items []T
+	less  func(a, b T) bool
+}
+
+func (h *boundedHeap[T]) Len() int           { return len(h.items) }
+func (h *boundedHeap[T]) Less(i, j int) bool { return h.less(h.items[i], h.items[j]) }
+func (h *boundedHeap[T]) Swap(i, j int)      { h.items[i], h.items[j] = h.items[j], h.items[i] }
+
+// Push and Pop are never called, as offer uses heap.Fix to avoid boxing values.
+func (h *boundedHeap[T]) Push(any) { panic("unreachable") }
+func (h *boundedHeap[T]) Pop() any { panic("unreachable") }
+
+func (h *boundedHeap[T]) offer(t T) {
+	if len(h.items) < h.n {
+		h.items = append(h.items, t)
+		heap.Fix(h, len(h.items)-1)
+		return
+	}
+	if len(h.items) == 0 || !h.less(h.items[0], t) {
+		return
+	}
+	h.items[0] = t
+	heap.Fix(h, 0)
+}
@@ -2,7 +2,15 @@ package to_test
 
 import (
 	"context"
+	"errors"
+	"iter"
+	"math"
+	"math/rand/v2"
+	"os"
+	"path/filepath"
 	"slices"
+	"strconv"
+	"strings"
 	"testing"
 	"time"
 
@@ -32,6 +40,139 @@ func TestFirst(t *testing.T) {
 	}
 }
 
+func TestLast(t *testing.T) {
+	tests := []struct {
+		src           []int
+		want          int
+		wantFound     bool
+		wantEven      int
+		wantEvenFound bool
+	}{
+		{[]int{1, 2, 3, 4, 5}, 5, true, 4, true},
+		{[]int{1, 3}, 3, true, 0, false},
+		{nil, 0, false, 0, false},
+	}
+
+	for _, tt := range tests {
+		got, found := to.Last(slices.Values(tt.src))
+		if got != tt.want || found != tt.wantFound {
+			t.Errorf("Last(%v): got %v, %v want %v, %v", tt.src, got, found, tt.want, tt.wantFound)
+		}
+		got, found = to.LastFunc(slices.Values(tt.src), func(i int) bool { return i%2 == 0 })
+		if got != tt.wantEven || found != tt.wantEvenFound {
+			t.Errorf("LastFunc(%v, isEven): got %v, %v want %v, %v", tt.src, got, found, tt.wantEven, tt.wantEvenFound)
+		}
+	}
+}
+
+func TestNth(t *testing.T) {
+	tests := []struct {
+		src       []int
+		n         int
+		want      int
+		wantFound bool
+	}{
+		{[]int{5, 6, 7}, 0, 5, true},
+		{[]int{5, 6, 7}, 2, 7, true},
+		{[]int{5, 6, 7}, 3, 0, false},
+		{[]int{5, 6, 7}, -1, 0, false},
+		{nil, 0, 0, false},
+	}
+
+	for _, tt := range tests {
+		consumed := 0
+		src := func(yield func(int) bool) {
+			for _, v := range tt.src {
+				consumed++
+				if !yield(v) {
+					return
+				}
+			}
+		}
+		got, found := to.Nth(src, tt.n)
+		if got != tt.want || found != tt.wantFound {
+			t.Errorf("Nth(%v, %v): got %v, %v want %v, %v", tt.src, tt.n, got, found, tt.want, tt.wantFound)
+		}
+		if found && consumed != tt.n+1 {
+			t.Errorf("Nth(%v, %v): consumed %v values want %v", tt.src, tt.n, consumed, tt.n+1)
+		}
+	}
+}
+
+func TestSingle(t *testing.T) {
+	tests := []struct {
+		src     []int
+		want    int
+		wantErr error
+	}{
+		{[]int{5}, 5, nil},
+		{[]int{5, 6, 7}, 0, to.ErrTooManyValues},
+		{nil, 0, to.ErrNoValues},
+	}
+
+	for _, tt := range tests {
+		consumed := 0
+		src := func(yield func(int) bool) {
+			for _, v := range tt.src {
+				consumed++
+				if !yield(v) {
+					return
+				}
+			}
+		}
+		got, err := to.Single(src)
+		if got != tt.want || !errors.Is(err, tt.wantErr) {
+			t.Errorf("Single(%v): got %v, %v want %v, %v", tt.src, got, err, tt.want, tt.wantErr)
+		}
+		if consumed > 2 {
+			t.Errorf("Single(%v): consumed %v values want at most 2", tt.src, consumed)
+		}
+	}
+}
+
+func TestIndexOf(t *testing.T) {
+	tests := []struct {
+		src  []int
+		want int
+	}{
+		{[]int{1, 3, 4, 6}, 2},
+		{[]int{2}, 0},
+		{[]int{1, 3, 5}, -1},
+		{nil, -1},
+	}
+
+	for _, tt := range tests {
+		got := to.IndexOf(slices.Values(tt.src), func(i int) bool { return i%2 == 0 })
+		if got != tt.want {
+			t.Errorf("IndexOf(%v, isEven): got %v want %v", tt.src, got, tt.want)
+		}
+	}
+}
+
+func TestMismatch(t *testing.T) {
+	tests := []struct {
+		a, b      string
+		wantIndex int
+		wantA     rune
+		wantB     rune
+		wantOk    bool
+	}{
+		{"hello", "help!", 3, 'l', 'p', true},
+		{"hello", "hello", 5, 0, 0, false},
+		{"hello", "hell", 4, 'o', 0, true},
+		{"hell", "hello", 4, 0, 'o', true},
+		{"", "", 0, 0, 0, false},
+	}
+
+	for _, tt := range tests {
+		index, va, vb, ok := to.Mismatch(slices.Values([]rune(tt.a)), slices.Values([]rune(tt.b)))
+		if index != tt.wantIndex || va != tt.wantA || vb != tt.wantB || ok != tt.wantOk {
+			t.Errorf("Mismatch(%q, %q): got %v, %q, %q, %v want %v, %q, %q, %v",
+				tt.a, tt.b, index, va, vb, ok, tt.wantIndex, tt.wantA, tt.wantB, tt.wantOk)
+		}
+	}
+}
+
 func TestContains(t *testing.T) {
 	got := to.Contains(slices.Values([]int{1, 2, 3, 4, 5}), func(i int) bool { return i == 3 })
 	if !got {
@@ -81,6 +222,29 @@ func TestMax(t *testing.T) {
 	}
 }
 
+func TestMinMax(t *testing.T) {
+	tests := []struct {
+		src     []int
+		wantMin int
+		wantMax int
+		wantOk  bool
+	}{
+		{[]int{8, 2, 3, 10, 7}, 2, 10, true},
+		{[]int{4}, 4, 4, true},
+		{nil, 0, 0, false},
+	}
+
+	for _, tt := range tests {
+		gotMin, gotMax, ok := to.MinMax(slices.Values(tt.src))
+		if gotMin != tt.wantMin || gotMax != tt.wantMax {
+			t.Errorf("MinMax(%v): got values %v, %v want %v, %v", tt.src, gotMin, gotMax, tt.wantMin, tt.wantMax)
+		}
+		if ok != tt.wantOk {
+			t.Errorf("MinMax(%v): got ok %v want %v", tt.src, ok, tt.wantOk)
+		}
+	}
+}
+
 func TestLen(t *testing.T) {
 	tests := []struct {
 		src  []int
@@ -98,6 +262,53 @@ func TestLen(t *testing.T) {
 	}
 }
 
+func TestSumProduct(t *testing.T) {
+	tests := []struct {
+		src         []int
+		wantSum     int
+		wantProduct int
+	}{
+		{[]int{1, 2, 3, 4}, 10, 24},
+		{[]int{5}, 5, 5},
+		{nil, 0, 1},
+	}
+
+	for _, tt := range tests {
+		if got := to.Sum(slices.Values(tt.src)); got != tt.wantSum {
+			t.Errorf("Sum(%v): got %v want %v", tt.src, got, tt.wantSum)
+		}
+		if got := to.Product(slices.Values(tt.src)); got != tt.wantProduct {
+			t.Errorf("Product(%v): got %v want %v", tt.src, got, tt.wantProduct)
+		}
+	}
+
+	if got, want := to.Sum(slices.Values([]complex128{1 + 2i, 3 - 1i})), 4+1i; got != want {
+		t.Errorf("Sum(1+2i, 3-1i): got %v want %v", got, want)
+	}
+}
+
+func TestMean(t *testing.T) {
+	tests := []struct {
+		src    []int64
+		want   float64
+		wantOk bool
+	}{
+		{[]int64{1, 2, 3, 4}, 2.5, true},
+		{[]int64{math.MaxInt64, math.MaxInt64}, math.MaxInt64, true},
+		{nil, 0, false},
+	}
+
+	for _, tt := range tests {
+		got, ok := to.Mean(slices.Values(tt.src))
+		if got != tt.want {
+			t.Errorf("Mean(%v): got value %v want %v", tt.src, got, tt.want)
+		}
+		if ok != tt.wantOk {
+			t.Errorf("Mean(%v): got ok %v want %v", tt.src, ok, tt.wantOk)
+		}
+	}
+}
+
 func TestReduce(t *testing.T) {
 	tests := []struct {
 		src   []int
@@ -119,6 +330,35 @@ func TestReduce(t *testing.T) {
 	}
 }
 
+func TestReduce2(t *testing.T) {
+	src := []int{10, 20, 30, 40}
+	got := to.Reduce2(slices.All(src), 0, func(accum, k, v int) (int, bool) {
+		return accum + k*v, k < 2
+	})
+	if want := 0*10 + 1*20 + 2*30; got != want {
+		t.Errorf("Reduce2(%v, sum k*v until k=2): got %v want %v", src, got, want)
+	}
+}
+
+func TestFold2(t *testing.T) {
+	tests := []struct {
+		src  []string
+		want string
+	}{
+		{[]string{"a", "b", "c"}, "0a1b2c"},
+		{nil, ""},
+	}
+
+	for _, tt := range tests {
+		got := to.Fold2(slices.All(tt.src), "", func(accum string, k int, v string) string {
+			return accum + strconv.Itoa(k) + v
+		})
+		if got != tt.want {
+			t.Errorf("Fold2(%v, concat): got %q want %q", tt.src, got, tt.want)
+		}
+	}
+}
+
 func TestChan(t *testing.T) {
 	t.Run("values are emitted", func(t *testing.T) {
 		ctx := context.Background()
@@ -154,4 +394,738 @@ func TestChan(t *testing.T) {
 			t.Errorf("Chan(%v): got %v want less than 2 values", src, got)
 		}
 	})
+	t.Run("stats are reported", func(t *testing.T) {
+		ctx := context.Background()
+		src := []int{1, 2, 3, 4}
+		srci := slices.Values(src)
+		var st to.ChanStats
+		var reported bool
+		c := to.Chan(ctx, srci, 0, to.WithStats(func(s to.ChanStats) {
+			st = s
+			reported = true
+		}))
+		for range c {
+			time.Sleep(time.Millisecond)
+		}
+		if !reported {
+			t.Fatalf("Chan(%v, WithStats): stats not reported before close", src)
+		}
+		if st.Sent != len(src) {
+			t.Errorf("Chan(%v, WithStats): got %v sent want %v", src, st.Sent, len(src))
+		}
+		if st.SendBlocked < time.Millisecond {
+			t.Errorf("Chan(%v, WithStats) with slow consumer: got %v blocked want at least 1ms", src, st.SendBlocked)
+		}
+	})
+	t.Run("on done", func(t *testing.T) {
+		src := []int{1, 2, 3}
+		done := make(chan error, 1)
+		c := to.Chan(context.Background(), slices.Values(src), 0, to.WithOnDone(func(err error) {
+			done <- err
+		}))
+		for range c {
+		}
+		if err := <-done; err != nil {
+			t.Errorf("Chan(%v, WithOnDone) exhausted: got err %v want nil", src, err)
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		c = to.Chan(ctx, slices.Values(src), 0, to.WithOnDone(func(err error) {
+			done <- err
+		}))
+		cancel()
+		if err := <-done; !errors.Is(err, context.Canceled) {
+			t.Errorf("Chan(%v, WithOnDone) cancelled: got err %v want %v", src, err, context.Canceled)
+		}
+	})
+	t.Run("overflow policies", func(t *testing.T) {
+		src := []int{1, 2, 3, 4, 5}
+		tests := []struct {
+			policy      to.OverflowPolicy
+			buf         int
+			want        []int
+			wantDropped int
+		}{
+			{to.Block, 2, []int{1, 2, 3, 4, 5}, 0},
+			{to.DropNewest, 2, []int{1, 2}, 3},
+			{to.DropOldest, 2, []int{4, 5}, 3},
+			{to.SampleLatest, 3, []int{5}, 4},
+		}
+		for _, tt := range tests {
+			var st to.ChanStats
+			c := to.Chan(context.Background(), slices.Values(src), tt.buf, to.WithOverflow(tt.policy), to.WithStats(func(s to.ChanStats) {
+				st = s
+			}))
+			// Wait for the source to be exhausted before consuming anything.
+			for len(c) < min(cap(c), len(src)) {
+				time.Sleep(time.Millisecond)
+			}
+			time.Sleep(10 * time.Millisecond)
+			var got []int
+			for v := range c {
+				got = append(got, v)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("Chan(%v, policy %v): got %v want %v diff:\n%v", src, tt.policy, got, tt.want, diff)
+			}
+			if st.Dropped != tt.wantDropped {
+				t.Errorf("Chan(%v, policy %v): got %v dropped want %v", src, tt.policy, st.Dropped, tt.wantDropped)
+			}
+		}
+	})
+}
+
+func TestCountDistinct(t *testing.T) {
+	tests := []struct {
+		src  []string
+		want int
+	}{
+		{nil, 0},
+		{[]string{"a", "b", "a", "c", "b"}, 3},
+	}
+	for _, tt := range tests {
+		if got := to.CountDistinct(slices.Values(tt.src)); got != tt.want {
+			t.Errorf("CountDistinct(%v): got %v want %v", tt.src, got, tt.want)
+		}
+	}
+
+	t.Run("approx", func(t *testing.T) {
+		// splitmix64 finalizer, to spread consecutive integers over all bits.
+		hash := func(i int) uint64 {
+			x := uint64(i)
+			x = (x ^ x>>30) * 0xbf58476d1ce4e5b9
+			x = (x ^ x>>27) * 0x94d049bb133111eb
+			return x ^ x>>31
+		}
+		for _, n := range []int{0, 10, 1000, 100_000, 1_000_000} {
+			// Every value is emitted twice.
+			src := func(yield func(int) bool) {
+				for i := range 2 * n {
+					if !yield(i % n) {
+						return
+					}
+				}
+			}
+			got := to.CountDistinctApprox(src, hash)
+			if diff := math.Abs(float64(got - n)); diff > 0.03*float64(n) {
+				t.Errorf("CountDistinctApprox(%v distinct values): got %v, want within 3%%", n, got)
+			}
+		}
+	})
+}
+
+func TestString(t *testing.T) {
+	tests := []string{"", "hello", "héllo, 世界"}
+	for _, src := range tests {
+		if got := to.String(slices.Values([]rune(src))); got != src {
+			t.Errorf("String(%q runes): got %q want %q", src, got, src)
+		}
+		if got := to.StringFromBytes(slices.Values([]byte(src))); got != src {
+			t.Errorf("StringFromBytes(%q bytes): got %q want %q", src, got, src)
+		}
+	}
+}
+
+func TestSortedSlice(t *testing.T) {
+	src := []string{"pear", "fig", "banana", "apple"}
+	got := to.SortedSlice(slices.Values(src))
+	want := []string{"apple", "banana", "fig", "pear"}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("SortedSlice(%v): got %v want %v diff:\n%v", src, got, want, diff)
+	}
+
+	got = to.SortedSliceFunc(slices.Values(src), func(a, b string) int { return len(a) - len(b) })
+	want = []string{"fig", "pear", "apple", "banana"}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("SortedSliceFunc(%v, byLen): got %v want %v diff:\n%v", src, got, want, diff)
+	}
+}
+
+func TestIsSorted(t *testing.T) {
+	tests := []struct {
+		src  []int
+		want bool
+	}{
+		{nil, true},
+		{[]int{1}, true},
+		{[]int{1, 1, 2, 3}, true},
+		{[]int{1, 3, 2}, false},
+	}
+	for _, tt := range tests {
+		if got := to.IsSorted(slices.Values(tt.src)); got != tt.want {
+			t.Errorf("IsSorted(%v): got %v want %v", tt.src, got, tt.want)
+		}
+		desc := func(a, b int) int { return b - a }
+		rev := slices.Clone(tt.src)
+		slices.Reverse(rev)
+		if got := to.IsSortedFunc(slices.Values(rev), desc); got != tt.want {
+			t.Errorf("IsSortedFunc(%v, desc): got %v want %v", rev, got, tt.want)
+		}
+	}
+
+	t.Run("early exit", func(t *testing.T) {
+		var consumed int
+		src := func(yield func(int) bool) {
+			for _, i := range []int{1, 3, 2, 4, 5} {
+				consumed++
+				if !yield(i) {
+					return
+				}
+			}
+		}
+		to.IsSorted(src)
+		if consumed != 3 {
+			t.Errorf("IsSorted(1 3 2 4 5): consumed %v values want 3", consumed)
+		}
+	})
+}
+
+func TestPartition(t *testing.T) {
+	tests := []struct {
+		src     []int
+		wantYes []int
+		wantNo  []int
+	}{
+		{[]int{1, 2, 3, 4, 5}, []int{2, 4}, []int{1, 3, 5}},
+		{[]int{2, 4}, []int{2, 4}, nil},
+		{nil, nil, nil},
+	}
+
+	for _, tt := range tests {
+		yes, no := to.Partition(slices.Values(tt.src), func(i int) bool { return i%2 == 0 })
+		if diff := cmp.Diff(tt.wantYes, yes); diff != "" {
+			t.Errorf("Partition(%v, isEven): got yes %v want %v diff:\n%v", tt.src, yes, tt.wantYes, diff)
+		}
+		if diff := cmp.Diff(tt.wantNo, no); diff != "" {
+			t.Errorf("Partition(%v, isEven): got no %v want %v diff:\n%v", tt.src, no, tt.wantNo, diff)
+		}
+	}
+}
+
+func TestPages(t *testing.T) {
+	tests := []struct {
+		src  []int
+		size int
+		want [][]int
+	}{
+		{[]int{1, 2, 3, 4, 5}, 2, [][]int{{1, 2}, {3, 4}, {5}}},
+		{[]int{1, 2, 3, 4}, 2, [][]int{{1, 2}, {3, 4}}},
+		{[]int{1, 2}, 0, nil},
+		{nil, 2, nil},
+	}
+	for _, tt := range tests {
+		got := slices.Collect(to.Pages(slices.Values(tt.src), tt.size))
+		if diff := cmp.Diff(tt.want, got); diff != "" {
+			t.Errorf("Pages(%v, %v): got %v want %v diff:\n%v", tt.src, tt.size, got, tt.want, diff)
+		}
+	}
+}
+
+func TestPage(t *testing.T) {
+	src := []int{1, 2, 3, 4, 5}
+	tests := []struct {
+		page, size int
+		want       []int
+	}{
+		{0, 2, []int{1, 2}},
+		{1, 2, []int{3, 4}},
+		{2, 2, []int{5}},
+		{3, 2, nil},
+		{-1, 2, nil},
+		{0, 0, nil},
+	}
+	for _, tt := range tests {
+		var consumed int
+		counting := func(yield func(int) bool) {
+			for _, v := range src {
+				consumed++
+				if !yield(v) {
+					return
+				}
+			}
+		}
+		got := to.Page(counting, tt.page, tt.size)
+		if diff := cmp.Diff(tt.want, got); diff != "" {
+			t.Errorf("Page(%v, %v, %v): got %v want %v diff:\n%v", src, tt.page, tt.size, got, tt.want, diff)
+		}
+		if wantMax := (tt.page + 1) * tt.size; consumed > wantMax && tt.size > 0 {
+			t.Errorf("Page(%v, %v, %v): consumed %v values want at most %v", src, tt.page, tt.size, consumed, wantMax)
+		}
+	}
+}
+
+func TestUnzip(t *testing.T) {
+	tests := []struct {
+		src        []string
+		wantKeys   []int
+		wantValues []string
+	}{
+		{[]string{"a", "b", "c"}, []int{0, 1, 2}, []string{"a", "b", "c"}},
+		{nil, nil, nil},
+	}
+
+	for _, tt := range tests {
+		gotKeys, gotValues := to.Unzip(slices.All(tt.src))
+		if diff := cmp.Diff(tt.wantKeys, gotKeys); diff != "" {
+			t.Errorf("Unzip(%v): got keys %v want %v diff:\n%v", tt.src, gotKeys, tt.wantKeys, diff)
+		}
+		if diff := cmp.Diff(tt.wantValues, gotValues); diff != "" {
+			t.Errorf("Unzip(%v): got values %v want %v diff:\n%v", tt.src, gotValues, tt.wantValues, diff)
+		}
+	}
+}
+
+func TestDuplicates(t *testing.T) {
+	tests := []struct {
+		src  []string
+		want map[string]int
+	}{
+		{[]string{"a", "b", "a", "c", "b", "a"}, map[string]int{"a": 3, "b": 2}},
+		{[]string{"a", "b", "c"}, map[string]int{}},
+		{nil, map[string]int{}},
+	}
+
+	for _, tt := range tests {
+		got := to.Duplicates(slices.Values(tt.src))
+		if diff := cmp.Diff(tt.want, got); diff != "" {
+			t.Errorf("Duplicates(%v): got %v want %v diff:\n%v", tt.src, got, tt.want, diff)
+		}
+	}
+}
+
+func TestMapGrouped(t *testing.T) {
+	words := strings.Fields("apple bird avocado cat banana")
+	byInitial := func(yield func(byte, string) bool) {
+		for _, w := range words {
+			if !yield(w[0], w) {
+				return
+			}
+		}
+	}
+	got := to.MapGrouped(byInitial)
+	want := map[byte][]string{
+		'a': {"apple", "avocado"},
+		'b': {"bird", "banana"},
+		'c': {"cat"},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("MapGrouped(%v by initial): got %v want %v diff:\n%v", words, got, want, diff)
+	}
+}
+
+func TestAggregateByKey(t *testing.T) {
+	words := strings.Fields("the cat and the dog and the bird")
+	withOnes := func(yield func(string, int) bool) {
+		for _, w := range words {
+			if !yield(w, 1) {
+				return
+			}
+		}
+	}
+	got := to.AggregateByKey(withOnes, 0, func(a, v int) int { return a + v })
+	want := map[string]int{"the": 3, "cat": 1, "and": 2, "dog": 1, "bird": 1}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("AggregateByKey(%v, sum): got %v want %v diff:\n%v", words, got, want, diff)
+	}
+}
+
+func TestFill(t *testing.T) {
+	tests := []struct {
+		src      []int
+		size     int
+		want     []int
+		wantMore bool
+	}{
+		{[]int{1, 2, 3, 4}, 2, []int{1, 2}, true},
+		{[]int{1, 2}, 2, []int{1, 2}, true},
+		{[]int{1}, 3, []int{1}, false},
+		{nil, 2, []int{}, false},
+	}
+
+	for _, tt := range tests {
+		dst := make([]int, tt.size)
+		n, more := to.Fill(slices.Values(tt.src), dst)
+		if diff := cmp.Diff(tt.want, dst[:n]); diff != "" {
+			t.Errorf("Fill(%v, [%v]int): got %v want %v diff:\n%v", tt.src, tt.size, dst[:n], tt.want, diff)
+		}
+		if more != tt.wantMore {
+			t.Errorf("Fill(%v, [%v]int): got more %v want %v", tt.src, tt.size, more, tt.wantMore)
+		}
+	}
+
+	t.Run("paging loses no values", func(t *testing.T) {
+		for _, src := range [][]int{{1, 2, 3, 4, 5}, {1, 2, 3, 4}, nil} {
+			next, stop := iter.Pull(slices.Values(src))
+			resumable := func(yield func(int) bool) {
+				for {
+					v, ok := next()
+					if !ok || !yield(v) {
+						return
+					}
+				}
+			}
+			var got []int
+			buf := make([]int, 2)
+			for {
+				n, more := to.Fill(resumable, buf)
+				got = append(got, buf[:n]...)
+				if !more {
+					break
+				}
+			}
+			stop()
+			if diff := cmp.Diff(src, got); diff != "" {
+				t.Errorf("Fill(%v) in pages of 2: got %v want %v diff:\n%v", src, got, src, diff)
+			}
+		}
+	})
+}
+
+func TestShard(t *testing.T) {
+	t.Run("values are routed", func(t *testing.T) {
+		shards := make([][]int, 3)
+		err := to.Shard(slices.Values([]int{1, 2, 3, 4, 5, 6, 7}), 3, func(i int) int {
+			return i % 3
+		}, func(shard int, v int) error {
+			shards[shard] = append(shards[shard], v)
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("Shard(1->7, %%3): got err %v want nil", err)
+		}
+		want := [][]int{{3, 6}, {1, 4, 7}, {2, 5}}
+		if diff := cmp.Diff(want, shards); diff != "" {
+			t.Errorf("Shard(1->7, %%3): got %v want %v diff:\n%v", shards, want, diff)
+		}
+	})
+	t.Run("errors stop consumption", func(t *testing.T) {
+		var got []int
+		wantErr := errors.New("disk full")
+		err := to.Shard(slices.Values([]int{1, 2, 3, 4}), 2, func(i int) int {
+			return i % 2
+		}, func(_ int, v int) error {
+			if v == 3 {
+				return wantErr
+			}
+			got = append(got, v)
+			return nil
+		})
+		if !errors.Is(err, wantErr) {
+			t.Errorf("Shard(1->4) failing at 3: got err %v want %v", err, wantErr)
+		}
+		if want := []int{1, 2}; !cmp.Equal(want, got) {
+			t.Errorf("Shard(1->4) failing at 3: got %v want %v", got, want)
+		}
+	})
+	t.Run("pick out of range", func(t *testing.T) {
+		err := to.Shard(slices.Values([]int{1}), 2, func(int) int {
+			return 2
+		}, func(int, int) error {
+			return nil
+		})
+		if err == nil {
+			t.Errorf("Shard(1, pick=2 of 2): got nil err, want error")
+		}
+	})
+}
+
+func TestDrain(t *testing.T) {
+	t.Run("all values are consumed", func(t *testing.T) {
+		n, err := to.Drain(context.Background(), slices.Values([]int{1, 2, 3}))
+		if n != 3 || err != nil {
+			t.Errorf("Drain(1->3): got %v, %v want 3, nil", n, err)
+		}
+	})
+	t.Run("cancellation is handled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		cancelAt3 := func(yield func(int) bool) {
+			for i := range 10 {
+				if i == 3 {
+					cancel()
+				}
+				if !yield(i) {
+					return
+				}
+			}
+		}
+		n, err := to.Drain(ctx, cancelAt3)
+		if n != 3 || !errors.Is(err, context.Canceled) {
+			t.Errorf("Drain(0 1 2 CANCELLED): got %v, %v want 3, %v", n, err, context.Canceled)
+		}
+	})
+}
+
+func TestForEach(t *testing.T) {
+	t.Run("all values are consumed", func(t *testing.T) {
+		var got []int
+		err := to.ForEach(context.Background(), slices.Values([]int{1, 2, 3}), func(i int) error {
+			got = append(got, i)
+			return nil
+		})
+		if err != nil {
+			t.Errorf("ForEach(1->3): got err %v want nil", err)
+		}
+		if want := []int{1, 2, 3}; !cmp.Equal(want, got) {
+			t.Errorf("ForEach(1->3): got %v want %v", got, want)
+		}
+	})
+	t.Run("errors stop consumption", func(t *testing.T) {
+		var got []int
+		wantErr := errors.New("too big")
+		err := to.ForEach(context.Background(), slices.Values([]int{1, 2, 3}), func(i int) error {
+			if i > 1 {
+				return wantErr
+			}
+			got = append(got, i)
+			return nil
+		})
+		if !errors.Is(err, wantErr) {
+			t.Errorf("ForEach(1->3) failing at 2: got err %v want %v", err, wantErr)
+		}
+		if want := []int{1}; !cmp.Equal(want, got) {
+			t.Errorf("ForEach(1->3) failing at 2: got %v want %v", got, want)
+		}
+	})
+	t.Run("cancellation is handled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		var got []int
+		err := to.ForEach(ctx, slices.Values([]int{1, 2, 3}), func(i int) error {
+			got = append(got, i)
+			cancel()
+			return nil
+		})
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("ForEach(1 CANCELLED): got err %v want %v", err, context.Canceled)
+		}
+		if want := []int{1}; !cmp.Equal(want, got) {
+			t.Errorf("ForEach(1 CANCELLED): got %v want %v", got, want)
+		}
+	})
+}
+
+// limitSink accepts up to limit values and fails on Close if err is set.
+type limitSink struct {
+	limit  int
+	got    []int
+	closed bool
+	err    error
+}
+
+func (s *limitSink) Accept(v int) bool {
+	s.got = append(s.got, v)
+	return len(s.got) < s.limit
+}
+
+func (s *limitSink) Close() error {
+	s.closed = true
+	return s.err
+}
+
+func TestInto(t *testing.T) {
+	src := []int{1, 2, 3, 4}
+	tests := []struct {
+		limit   int
+		err     error
+		want    []int
+		wantErr bool
+	}{
+		{10, nil, []int{1, 2, 3, 4}, false},
+		{2, nil, []int{1, 2}, false},
+		{10, errors.New("close failed"), []int{1, 2, 3, 4}, true},
+	}
+	for _, tt := range tests {
+		sink := &limitSink{limit: tt.limit, err: tt.err}
+		err := to.Into(slices.Values(src), sink)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("Into(%v, limit %v): got err %v want err %v", src, tt.limit, err, tt.wantErr)
+		}
+		if diff := cmp.Diff(tt.want, sink.got); diff != "" {
+			t.Errorf("Into(%v, limit %v): got %v want %v diff:\n%v", src, tt.limit, sink.got, tt.want, diff)
+		}
+		if !sink.closed {
+			t.Errorf("Into(%v, limit %v): sink not closed", src, tt.limit)
+		}
+	}
+}
+
+func TestRunAll(t *testing.T) {
+	t.Run("all succeed", func(t *testing.T) {
+		var sumA, sumB int
+		err := to.RunAll(context.Background(),
+			to.Job(slices.Values([]int{1, 2, 3}), func(i int) error {
+				sumA += i
+				return nil
+			}),
+			to.Job(slices.Values([]int{4, 5}), func(i int) error {
+				sumB += i
+				return nil
+			}),
+		)
+		if err != nil {
+			t.Fatalf("RunAll: got err %v want nil", err)
+		}
+		if sumA != 6 || sumB != 9 {
+			t.Errorf("RunAll: got sums %v %v want 6 9", sumA, sumB)
+		}
+	})
+	t.Run("first error cancels the others", func(t *testing.T) {
+		wantErr := errors.New("broken")
+		infinite := func(yield func(int) bool) {
+			for {
+				if !yield(0) {
+					return
+				}
+			}
+		}
+		err := to.RunAll(context.Background(),
+			to.Job(slices.Values([]int{1}), func(int) error {
+				return wantErr
+			}),
+			to.Job(infinite, func(int) error {
+				return nil
+			}),
+		)
+		if !errors.Is(err, wantErr) {
+			t.Errorf("RunAll: got err %v want %v", err, wantErr)
+		}
+	})
+}
+
+func TestSampleWeighted(t *testing.T) {
+	r := rand.New(rand.NewPCG(1, 2))
+	src := []string{"never", "rare", "common", "common2"}
+	weights := map[string]float64{"never": 0, "rare": 1, "common": 1000, "common2": 1000}
+	counts := map[string]int{}
+	for range 1000 {
+		got := to.SampleWeighted(slices.Values(src), 2, func(s string) float64 { return weights[s] }, r)
+		if len(got) != 2 {
+			t.Fatalf("SampleWeighted(%v, 2): got %v want 2 values", src, got)
+		}
+		for _, s := range got {
+			counts[s]++
+		}
+	}
+	if counts["never"] != 0 {
+		t.Errorf("SampleWeighted(%v, 2): got %v samples with weight 0 want 0", src, counts["never"])
+	}
+	if counts["rare"] >= counts["common"] {
+		t.Errorf("SampleWeighted(%v, 2): got rare sampled %v times and common %v times, want rare < common", src, counts["rare"], counts["common"])
+	}
+
+	if got := to.SampleWeighted(slices.Values(src), 10, func(s string) float64 { return weights[s] }, r); len(got) != 3 {
+		t.Errorf("SampleWeighted(%v, 10): got %v want the 3 values with positive weight", src, got)
+	}
+}
+
+func TestTopN(t *testing.T) {
+	tests := []struct {
+		src  []int
+		n    int
+		want []int
+	}{
+		{[]int{5, 1, 9, 3, 7, 2, 8}, 3, []int{9, 8, 7}},
+		{[]int{2, 1}, 5, []int{2, 1}},
+		{[]int{2, 1}, 0, nil},
+		{nil, 3, nil},
+	}
+
+	for _, tt := range tests {
+		got := to.TopN(slices.Values(tt.src), tt.n, func(a, b int) int { return a - b })
+		if diff := cmp.Diff(tt.want, got); diff != "" {
+			t.Errorf("TopN(%v, %v): got %v want %v diff:\n%v", tt.src, tt.n, got, tt.want, diff)
+		}
+	}
+}
+
+type failingWriter struct {
+	limit int
+}
+
+func (f *failingWriter) Write(b []byte) (int, error) {
+	if len(b) > f.limit {
+		n := f.limit
+		f.limit = 0
+		return n, errors.New("no space left")
+	}
+	f.limit -= len(b)
+	return len(b), nil
+}
+
+func TestWriter(t *testing.T) {
+	t.Run("bytes", func(t *testing.T) {
+		var sb strings.Builder
+		src := [][]byte{[]byte("foo"), []byte("bar")}
+		n, err := to.Writer(&sb, slices.Values(src))
+		if err != nil || n != 6 || sb.String() != "foobar" {
+			t.Errorf("Writer(foo bar): got %v, %v and wrote %q want 6, nil and %q", n, err, sb.String(), "foobar")
+		}
+	})
+	t.Run("strings", func(t *testing.T) {
+		var sb strings.Builder
+		src := []string{"foo", "bar"}
+		n, err := to.WriterString(&sb, slices.Values(src), "\n")
+		if err != nil || n != 8 || sb.String() != "foo\nbar\n" {
+			t.Errorf("WriterString(foo bar, \\n): got %v, %v and wrote %q want 8, nil and %q", n, err, sb.String(), "foo\nbar\n")
+		}
+	})
+	t.Run("errors stop consumption", func(t *testing.T) {
+		w := &failingWriter{limit: 4}
+		n, err := to.WriterString(w, slices.Values([]string{"foo", "bar", "baz"}), "")
+		if err == nil || n != 4 {
+			t.Errorf("WriterString(foo bar baz) with 4 bytes of space: got %v, %v want 4, error", n, err)
+		}
+	})
+}
+
+func TestJSONLines(t *testing.T) {
+	type record struct {
+		Name string `json:"name"`
+		Age  int    `json:"age"`
+	}
+	src := []record{{"Alice", 30}, {"Bob", 25}}
+	var sb strings.Builder
+	if err := to.JSONLines(&sb, slices.Values(src)); err != nil {
+		t.Fatalf("JSONLines(%v): got err %v want nil", src, err)
+	}
+	want := `{"name":"Alice","age":30}
+{"name":"Bob","age":25}
+`
+	if diff := cmp.Diff(want, sb.String()); diff != "" {
+		t.Errorf("JSONLines(%v): got %q want %q diff:\n%v", src, sb.String(), want, diff)
+	}
+
+	if err := to.JSONLines(&sb, slices.Values([]any{func() {}})); err == nil {
+		t.Errorf("JSONLines(func): got nil err, want error")
+	}
+}
+
+func TestFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "out.txt")
+	if err := to.File(path, slices.Values([]string{"foo", "bar"})); err != nil {
+		t.Fatalf("File(foo bar): got err %v want nil", err)
+	}
+	if err := to.File(path, slices.Values([]string{"baz"}), to.WithAppend()); err != nil {
+		t.Fatalf("File(baz, WithAppend): got err %v want nil", err)
+	}
+	got, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+	if want := "foo\nbar\nbaz\n"; string(got) != want {
+		t.Errorf("File(foo bar) then File(baz, WithAppend): got %q want %q", got, want)
+	}
+
+	if err := to.File(path, slices.Values([]string{"qux"}), to.WithFileMode(0o600)); err != nil {
+		t.Fatalf("File(qux): got err %v want nil", err)
+	}
+	if got, _ := os.ReadFile(path); string(got) != "qux\n" {
+		t.Errorf("File(qux) on existing file: got %q want %q", got, "qux\n")
+	}
+
+	missing := filepath.Join(t.TempDir(), "missing", "out.txt")
+	if err := to.File(missing, slices.Values([]string{"foo"})); err == nil {
+		t.Errorf("File(%q): got nil err, want error", missing)
+	}
 }